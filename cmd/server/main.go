@@ -7,12 +7,18 @@ import (
 	"os"
 
 	"github.com/eightjhonydolly/05.12.2025/internal/app"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/config"
 )
 
 func main() {
 	fmt.Println("Link checker service starting...")
 
-	app, err := app.NewApp(os.Getenv("CONFIG_ENV_VAR"))
+	loader, err := config.ParseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatal("Invalid arguments:", err)
+	}
+
+	app, err := app.NewAppFromLoader(loader)
 	if err != nil {
 		log.Fatal("Failed to create app:", err)
 	}
@@ -3,3 +3,11 @@ module github.com/eightjhonydolly/05.12.2025
 go 1.22.4
 
 require github.com/jung-kurt/gofpdf v1.16.2
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.3.1
+	golang.org/x/net v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/text v0.21.0 // indirect
@@ -2,45 +2,102 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/api_keys_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/check_documents_handler"
 	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/check_links_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/check_sitemap_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/crawl_handler"
 	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/generate_report_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/usage_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/openapi"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	keyrepository "github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/repository"
+	keyservice "github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	quotarepository "github.com/eightjhonydolly/05.12.2025/internal/domain/quota/repository"
+	quotaservice "github.com/eightjhonydolly/05.12.2025/internal/domain/quota/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
 	"github.com/eightjhonydolly/05.12.2025/internal/infra/config"
 	"github.com/eightjhonydolly/05.12.2025/internal/infra/http/middlewares"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/jwtauth"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/proxy"
 )
 
+const jwksTimeout = 10 * time.Second
+
 type App struct {
+	mu     sync.Mutex
 	config *config.Config
+	loader *config.Loader
 	server http.Server
+
+	services *services
+	handler  swappableHandler
+	logLevel slog.LevelVar
 }
 
 func NewApp(configPath string) (*App, error) {
-	configImpl, err := config.LoadConfig(configPath)
+	return NewAppFromLoader(&config.Loader{Path: configPath})
+}
+
+func NewAppFromLoader(loader *config.Loader) (*App, error) {
+	configImpl, err := loader.Load()
 	if err != nil {
-		return nil, fmt.Errorf("config.LoadConfig: %w", err)
+		return nil, fmt.Errorf("config.Load: %w", err)
 	}
 
 	app := &App{
 		config: configImpl,
+		loader: loader,
+		server: http.Server{
+			ReadTimeout:       configImpl.Server.ReadTimeout,
+			ReadHeaderTimeout: configImpl.Server.ReadHeaderTimeout,
+			WriteTimeout:      configImpl.Server.WriteTimeout,
+			IdleTimeout:       configImpl.Server.IdleTimeout,
+		},
 	}
 
-	app.server.Handler = bootstrapHandler()
+	app.setLogLevel(configImpl.Logging.Level)
+	slog.SetDefault(newLogger(configImpl.Logging, &app.logLevel))
+
+	repos, err := newRepositories(configImpl)
+	if err != nil {
+		return nil, err
+	}
+	app.services, err = newServices(configImpl, repos)
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := buildHandler(configImpl, app.services)
+	if err != nil {
+		return nil, fmt.Errorf("buildHandler: %w", err)
+	}
+	app.handler.Store(handler)
+	app.server.Handler = &app.handler
 
 	return app, nil
 }
 
 func (app *App) ListenAndServe() error {
-	address := fmt.Sprintf("%s:%s", app.config.Server.Host, app.config.Server.Port)
+	address := net.JoinHostPort(app.config.Server.Host, app.config.Server.Port)
 
 	log.Printf("Starting server on %s", address)
 	l, err := net.Listen("tcp", address)
@@ -50,6 +107,8 @@ func (app *App) ListenAndServe() error {
 	}
 
 	go app.gracefulShutdown()
+	go app.reloadOnSignal()
+	app.watchConfigFile(nil)
 
 	log.Printf("Server listening on %s", address)
 	return app.server.Serve(l)
@@ -61,7 +120,7 @@ func (app *App) gracefulShutdown() {
 	<-sigChan
 
 	log.Println("Received shutdown signal, starting graceful shutdown...")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), app.currentConfig().Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := app.server.Shutdown(ctx); err != nil {
@@ -71,15 +130,451 @@ func (app *App) gracefulShutdown() {
 	}
 }
 
-func bootstrapHandler() http.Handler {
-	linkRepository := repository.NewInMemoryLinkRepository()
-	linkService := service.NewLinkService(linkRepository)
+func bootstrapHandler(cfg *config.Config) (http.Handler, error) {
+	repos, err := newRepositories(cfg)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := newServices(cfg, repos)
+	if err != nil {
+		return nil, err
+	}
+	return buildHandler(cfg, svc)
+}
+
+// services outlive reloads. They hold the quota reservations, the API keys
+// and the JWKS cache, so Reload passes them the new settings instead of
+// replacing them.
+type services struct {
+	repos  *repositories
+	links  service.LinkService
+	quotas quotaservice.QuotaService
+	keys   keyservice.KeyService
+	auth   middlewares.Authenticator
+
+	jwks    *jwtauth.KeySet
+	jwksCfg config.JWTConfig
+}
+
+func newServices(cfg *config.Config, repos *repositories) (*services, error) {
+	svc := &services{
+		repos:  repos,
+		quotas: quotaservice.NewQuotaService(repos.usage, repos.links),
+		keys:   keyservice.NewKeyService(repos.keys),
+	}
+	if err := svc.configure(cfg); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// configure applies cfg to the services. Nothing changes when it fails.
+func (svc *services) configure(cfg *config.Config) error {
+	links, err := newLinkService(cfg, svc.repos.links)
+	if err != nil {
+		return err
+	}
+
+	svc.links = links
+	svc.quotas.Configure(newQuotaOptions(cfg)...)
+	svc.keys.Configure(keyservice.WithStaticKeys(newStaticKeys(cfg.Auth.Keys)))
+	svc.auth = newAuthenticator(cfg.Auth.JWT, svc.keys, svc.keySet(cfg.Auth.JWT))
+	return nil
+}
+
+// keySet keeps the cached JWKS unless its URL or cache TTL changed.
+func (svc *services) keySet(cfg config.JWTConfig) *jwtauth.KeySet {
+	if cfg.JWKSURL == "" {
+		return nil
+	}
+	if svc.jwks == nil || cfg.JWKSURL != svc.jwksCfg.JWKSURL || cfg.CacheTTL != svc.jwksCfg.CacheTTL {
+		svc.jwks = jwtauth.NewKeySet(cfg.JWKSURL, &http.Client{Timeout: jwksTimeout}, cfg.CacheTTL)
+		svc.jwksCfg = cfg
+	}
+	return svc.jwks
+}
+
+func newLinkService(cfg *config.Config, repo repository.LinkRepository) (service.LinkService, error) {
+	policy, err := newDestinationPolicy(cfg.Checker.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("destination policy: %w", err)
+	}
+
+	schemeStrategy, err := service.ParseSchemeStrategy(cfg.Checker.SchemeStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("checker: %w", err)
+	}
+
+	clientOptions, err := newClientOptions(cfg.Checker.HTTP)
+	if err != nil {
+		return nil, fmt.Errorf("checker.http: %w", err)
+	}
+
+	proxySelector, err := newProxySelector(cfg.Checker.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("checker.proxy: %w", err)
+	}
+
+	serviceOptions := []service.Option{
+		service.WithTimeout(cfg.Checker.Timeout),
+		service.WithCertExpiryWarning(cfg.Checker.CertExpiryWarning),
+		service.WithDestinationPolicy(policy),
+		service.WithSchemeStrategy(schemeStrategy),
+		service.WithProxy(proxySelector),
+		service.WithCredentials(newCredentials(cfg.Checker.Credentials)),
+		service.WithCrawlLimits(cfg.Checker.Crawl.MaxPages, cfg.Checker.Crawl.MaxDepth, cfg.Checker.Crawl.MaxLinks),
+	}
+	serviceOptions = append(serviceOptions, clientOptions...)
+	if cfg.Checker.StripTrackingParams {
+		serviceOptions = append(serviceOptions, service.WithTrackingParamsStripped())
+	}
+
+	return service.NewLinkService(repo, serviceOptions...), nil
+}
+
+// buildHandler wires the routes around the long-lived services. The handler
+// itself holds no state and is rebuilt on every reload.
+func buildHandler(cfg *config.Config, svc *services) (http.Handler, error) {
+	linkService := quotaservice.NewLinkService(svc.links, svc.quotas)
+
+	// In api_key mode the rate limit runs after authentication so that it
+	// keys by the caller instead of whatever credentials the request
+	// carries; rejected credentials are charged to the client IP instead.
+	limitByPrincipal := cfg.RateLimit.Enabled && cfg.RateLimit.Key == "api_key"
+	authErrors := problem.WriteError
+	if limitByPrincipal {
+		authErrors = middlewares.RateLimitFailures(svc.repos.rateLimits, newRateLimit(cfg.RateLimit), middlewares.RateLimitByIP, problem.WriteError)
+	}
+	protect := func(scope model.Scope, h http.Handler) http.Handler {
+		if !cfg.Auth.Enabled {
+			return h
+		}
+		return middlewares.NewAuthMiddleware(h, svc.auth, scope, authErrors)
+	}
+	rateLimit := func(pattern string, h http.Handler) http.Handler {
+		if !cfg.RateLimit.Enabled {
+			return h
+		}
+		return middlewares.NewRateLimitMiddleware(h, svc.repos.rateLimits, newRateLimit(cfg.RateLimit), rateLimitKey(cfg.RateLimit.Key, pattern), problem.WriteError)
+	}
+
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	limits := newLimits(cfg)
+
+	// Requests are validated after authentication so that anonymous callers
+	// neither learn the schema nor make the service read large bodies.
+	validate := func(h http.Handler) http.Handler {
+		return openapi.NewValidationMiddleware(h, spec, maxBodyBytes(cfg))
+	}
 
 	mx := http.NewServeMux()
-	mx.Handle("POST /api/check-links", check_links_handler.NewCheckLinksHandler(linkService))
-	mx.Handle("POST /api/generate-report", generate_report_handler.NewGenerateReportHandler(linkService))
+	handle := func(pattern string, h http.Handler) {
+		mx.Handle(pattern, rateLimit(pattern, validate(h)))
+	}
+	handleProtected := func(pattern string, scope model.Scope, h http.Handler) {
+		if limitByPrincipal {
+			mx.Handle(pattern, protect(scope, rateLimit(pattern, validate(h))))
+			return
+		}
+		mx.Handle(pattern, rateLimit(pattern, protect(scope, validate(h))))
+	}
+
+	handleProtected("POST /api/check-links", model.ScopeCheckWrite, check_links_handler.NewCheckLinksHandler(linkService, limits...))
+	handleProtected("POST /api/v2/check-links", model.ScopeCheckWrite, check_links_handler.NewCheckLinksV2Handler(linkService, limits...))
+	handleProtected("POST /api/check-documents", model.ScopeCheckWrite, check_documents_handler.NewCheckDocumentsHandler(linkService, limits...))
+	handleProtected("POST /api/check-sitemap", model.ScopeCheckWrite, check_sitemap_handler.NewCheckSitemapHandler(linkService))
+	handleProtected("POST /api/crawl", model.ScopeCheckWrite, crawl_handler.NewCrawlHandler(linkService))
+	handleProtected("POST /api/generate-report", model.ScopeReportRead, generate_report_handler.NewGenerateReportHandler(linkService))
+	handleProtected("POST /api/v2/generate-report", model.ScopeReportRead, generate_report_handler.NewGenerateReportV2Handler(linkService))
+	handleProtected("GET /api/usage", model.ScopeCheckWrite, usage_handler.NewUsageHandler(svc.quotas))
 
-	middleware := middlewares.NewTimerMiddleware(mx)
+	if cfg.Auth.Enabled {
+		handleProtected("POST /api/admin/keys", model.ScopeAdmin, api_keys_handler.NewCreateKeyHandler(svc.keys))
+		handleProtected("GET /api/admin/keys", model.ScopeAdmin, api_keys_handler.NewListKeysHandler(svc.keys))
+		handleProtected("DELETE /api/admin/keys/{id}", model.ScopeAdmin, api_keys_handler.NewRevokeKeyHandler(svc.keys))
+	}
+
+	handle("GET /openapi.json", openapi.NewSpecHandler())
+	handle("GET /docs", openapi.NewDocsHandler())
+
+	middleware := middlewares.NewTimerMiddleware(middlewares.NewRequestIDMiddleware(mx))
+
+	return middleware, nil
+}
+
+func newDestinationPolicy(cfg config.DestinationConfig) (*netpolicy.Policy, error) {
+	allow, err := netpolicy.ParseCIDRs(cfg.AllowCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := netpolicy.ParseCIDRs(cfg.DenyCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &netpolicy.Policy{
+		AllowPrivate:   cfg.AllowPrivate,
+		AllowCIDRs:     allow,
+		DenyCIDRs:      deny,
+		AllowHosts:     cfg.AllowHosts,
+		DenyHosts:      cfg.DenyHosts,
+		AllowedPorts:   cfg.AllowedPorts,
+		AllowedSchemes: cfg.AllowedSchemes,
+	}, nil
+}
+
+type repositories struct {
+	links repository.LinkRepository
+	keys  keyrepository.KeyRepository
+	usage quotarepository.UsageRepository
+
+	rateLimits middlewares.RateLimitStore
+}
+
+func newRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.Storage.Backend {
+	case "memory":
+		return &repositories{
+			links: repository.NewInMemoryLinkRepository(),
+			keys:  keyrepository.NewInMemoryKeyRepository(),
+			usage: quotarepository.NewInMemoryUsageRepository(),
+
+			rateLimits: middlewares.NewInMemoryRateLimitStore(cfg.RateLimit.IdleTimeout),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+type bearerAuthenticator struct {
+	keys middlewares.Authenticator
+	jwt  middlewares.Authenticator
+}
+
+func (a *bearerAuthenticator) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	if a.jwt != nil && jwtauth.LooksLikeJWT(token) {
+		return a.jwt.Authenticate(ctx, token)
+	}
+	return a.keys.Authenticate(ctx, token)
+}
+
+func newAuthenticator(cfg config.JWTConfig, keys middlewares.Authenticator, keySet *jwtauth.KeySet) middlewares.Authenticator {
+	if keySet == nil {
+		return keys
+	}
+
+	scopeMap := make(map[string]model.Scope, len(cfg.ScopeMap))
+	for value, scope := range cfg.ScopeMap {
+		scopeMap[value] = model.Scope(scope)
+	}
+
+	return &bearerAuthenticator{
+		keys: keys,
+		jwt: &jwtauth.Authenticator{
+			Keys:       keySet,
+			Issuer:     cfg.Issuer,
+			Audience:   cfg.Audience,
+			Leeway:     cfg.Leeway,
+			ScopeClaim: cfg.ScopeClaim,
+			ScopeMap:   scopeMap,
+
+			TenantClaim: cfg.TenantClaim,
+		},
+	}
+}
+
+func newLimits(cfg *config.Config) []check_links_handler.Option {
+	opts := []check_links_handler.Option{check_links_handler.WithLimits(apiLimits(cfg.API))}
+	for _, t := range cfg.Tenants {
+		opts = append(opts, check_links_handler.WithTenantLimits(t.Name, apiLimits(t.API)))
+	}
+	return opts
+}
+
+// maxBodyBytes is the largest body any tenant may send. The validation
+// middleware is shared by all tenants, so the handlers apply the limit of the
+// caller's tenant.
+func maxBodyBytes(cfg *config.Config) int64 {
+	limit := cfg.API.MaxBodyBytes
+	for _, t := range cfg.Tenants {
+		limit = max(limit, t.API.MaxBodyBytes)
+	}
+	return limit
+}
+
+func apiLimits(cfg config.APIConfig) check_links_handler.Limits {
+	return check_links_handler.Limits{
+		MaxBodyBytes: cfg.MaxBodyBytes,
+		MaxLinks:     cfg.MaxLinks,
+		MaxURLLength: cfg.MaxURLLength,
+	}
+}
+
+func rateLimitKey(key, pattern string) middlewares.RateLimitKey {
+	switch key {
+	case "api_key":
+		return middlewares.RateLimitByPrincipal
+	case "route":
+		return middlewares.RateLimitByRoute(pattern)
+	default:
+		return middlewares.RateLimitByIP
+	}
+}
+
+func newRateLimit(cfg config.RateLimitConfig) middlewares.RateLimit {
+	return middlewares.RateLimit{
+		Burst:  cfg.Burst,
+		Refill: cfg.RefillRate,
+		Period: cfg.RefillPeriod,
+	}
+}
+
+func newQuotaOptions(cfg *config.Config) []quotaservice.Option {
+	opts := []quotaservice.Option{quotaservice.WithDefaultQuota(newQuota(cfg.Quota))}
+	for _, t := range cfg.Tenants {
+		opts = append(opts, quotaservice.WithTenantQuota(t.Name, newQuota(t.Quota)))
+	}
+	return opts
+}
+
+func newQuota(cfg config.QuotaConfig) model.Quota {
+	return model.Quota{
+		ChecksPerHour:     cfg.ChecksPerHour,
+		LinksPerBatch:     cfg.LinksPerBatch,
+		ConcurrentBatches: cfg.ConcurrentBatches,
+		StoredBatches:     cfg.StoredBatches,
+	}
+}
+
+func newStaticKeys(cfg []config.KeyConfig) []*model.APIKey {
+	keys := make([]*model.APIKey, len(cfg))
+	for i, key := range cfg {
+		keyTenant := key.Tenant
+		if keyTenant == "" {
+			keyTenant = tenant.Default
+		}
+		keys[i] = &model.APIKey{
+			ID:     "config-" + key.Name,
+			Name:   key.Name,
+			Tenant: keyTenant,
+			Quota:  newQuota(key.Quota),
+			Hash:   strings.ToLower(key.Hash),
+			Scopes: make([]model.Scope, len(key.Scopes)),
+		}
+		for j, scope := range key.Scopes {
+			keys[i].Scopes[j] = model.Scope(scope)
+		}
+	}
+	return keys
+}
+
+func newLogger(cfg config.LoggingConfig, level *slog.LevelVar) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, opts))
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func newProxySelector(cfg config.ProxyConfig) (*proxy.Selector, error) {
+	selector := &proxy.Selector{}
+	if cfg.FromEnvironment && cfg.URL == "" {
+		var err error
+		if selector, err = proxy.FromEnvironment(); err != nil {
+			return nil, err
+		}
+	} else {
+		proxyURL, err := proxy.Parse(cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		selector.HTTP, selector.HTTPS = proxyURL, proxyURL
+	}
+
+	selector.NoProxy = append(selector.NoProxy, cfg.NoProxy...)
+	for _, rule := range cfg.Rules {
+		proxyURL, err := proxy.Parse(rule.URL)
+		if err != nil {
+			return nil, err
+		}
+		selector.Rules = append(selector.Rules, proxy.Rule{Hosts: rule.Hosts, URL: proxyURL})
+	}
+
+	if selector.HTTP == nil && selector.HTTPS == nil && len(selector.Rules) == 0 {
+		return nil, nil
+	}
+	return selector, nil
+}
+
+func parseHeaders(lines []string) http.Header {
+	headers := make(http.Header)
+	for _, header := range lines {
+		name, value, _ := strings.Cut(header, ":")
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return headers
+}
+
+func newCredentials(cfg []config.CredentialConfig) map[string]service.Credential {
+	credentials := make(map[string]service.Credential, len(cfg))
+	for _, credential := range cfg {
+		credentials[credential.Name] = service.Credential{
+			Username: credential.Username,
+			Password: credential.Password,
+			Token:    credential.Token,
+			Headers:  parseHeaders(credential.Headers),
+			Hosts:    credential.Hosts,
+			Tenant:   credential.Tenant,
+		}
+	}
+	return credentials
+}
+
+func newClientOptions(cfg config.HTTPClientConfig) ([]service.Option, error) {
+	headers := parseHeaders(cfg.Headers)
+
+	opts := []service.Option{
+		service.WithConnectTimeout(cfg.ConnectTimeout),
+		service.WithTLSHandshakeTimeout(cfg.TLSHandshakeTimeout),
+		service.WithResponseHeaderTimeout(cfg.ResponseHeaderTimeout),
+		service.WithMaxIdleConnsPerHost(cfg.MaxIdleConnsPerHost),
+		service.WithKeepAlive(cfg.KeepAlive),
+		service.WithHTTP2(cfg.HTTP2),
+		service.WithUserAgent(cfg.UserAgent),
+		service.WithDefaultHeaders(headers),
+		service.WithTLSMinVersion(tlsVersions[cfg.TLSMinVersion]),
+		service.WithInsecureSkipVerify(cfg.InsecureSkipVerify),
+	}
+	if cfg.InsecureSkipVerify {
+		log.Println("TLS certificate verification is disabled for link checks")
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("ca_bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_bundle: no certificates found in %s", cfg.CABundle)
+		}
+		opts = append(opts, service.WithRootCAs(pool))
+	}
 
-	return middleware
+	return opts, nil
 }
@@ -2,10 +2,25 @@ package app
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	keyservice "github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/config"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/http/middlewares"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func TestApp_Integration_CheckLinksAndGenerateReport(t *testing.T) {
@@ -104,13 +119,13 @@ func TestApp_Integration_InvalidRequests(t *testing.T) {
 
 func TestNewApp_ConfigError(t *testing.T) {
 
-	app, err := NewApp("invalid/path")
-	if err != nil {
-		t.Fatalf("NewApp should not fail with invalid config path: %v", err)
+	if _, err := NewApp("invalid/path"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected missing config file to be reported, got %v", err)
 	}
 
-	if app == nil {
-		t.Fatal("Expected app instance, got nil")
+	app, err := NewApp("")
+	if err != nil {
+		t.Fatalf("NewApp should work with defaults: %v", err)
 	}
 
 	if app.config == nil {
@@ -119,7 +134,15 @@ func TestNewApp_ConfigError(t *testing.T) {
 }
 
 func TestBootstrapHandler(t *testing.T) {
-	handler := bootstrapHandler()
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	handler, err := bootstrapHandler(cfg)
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
 	if handler == nil {
 		t.Fatal("Expected handler, got nil")
 	}
@@ -150,4 +173,665 @@ func TestApp_ListenAndServe_InvalidAddress(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for invalid address, got nil")
 	}
-}
\ No newline at end of file
+}
+
+func TestApp_CheckLinks_BlocksInternalDestinations(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer internal.Close()
+
+	app, err := NewApp("")
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	server := httptest.NewServer(app.server.Handler)
+	defer server.Close()
+
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"links": []string{internal.URL, "http://169.254.169.254/latest/meta-data/"},
+	})
+
+	resp, err := http.Post(server.URL+"/api/check-links", "application/json", bytes.NewReader(reqBody))
+	if err != nil {
+		t.Fatalf("Check links request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var checkResp struct {
+		Links map[string]string `json:"links"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&checkResp); err != nil {
+		t.Fatalf("Failed to decode check response: %v", err)
+	}
+
+	for link, status := range checkResp.Links {
+		if status != "blocked" {
+			t.Errorf("Expected %s to be blocked, got %s", link, status)
+		}
+	}
+}
+
+func TestBootstrapHandler_InvalidDestinationPolicy(t *testing.T) {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Checker.Destination.DenyCIDRs = []string{"not-a-cidr"}
+
+	if _, err := bootstrapHandler(cfg); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+}
+
+func TestApp_OpenAPISpecCoversRoutes(t *testing.T) {
+	app, err := NewApp("")
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	server := httptest.NewServer(app.server.Handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("Spec request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var spec struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("Failed to decode spec: %v", err)
+	}
+
+	if spec.OpenAPI == "" {
+		t.Error("Expected openapi version")
+	}
+
+	routes := []string{
+		"/api/check-links",
+		"/api/v2/check-links",
+		"/api/check-documents",
+		"/api/check-sitemap",
+		"/api/crawl",
+		"/api/generate-report",
+		"/api/v2/generate-report",
+	}
+	for _, route := range routes {
+		if _, ok := spec.Paths[route]["post"]; !ok {
+			t.Errorf("Expected spec to describe POST %s", route)
+		}
+	}
+	for _, route := range []string{"/openapi.json", "/docs", "/api/usage"} {
+		if _, ok := spec.Paths[route]["get"]; !ok {
+			t.Errorf("Expected spec to describe GET %s", route)
+		}
+	}
+
+	docs, err := http.Get(server.URL + "/docs")
+	if err != nil {
+		t.Fatalf("Docs request failed: %v", err)
+	}
+	defer docs.Body.Close()
+
+	if docs.StatusCode != http.StatusOK {
+		t.Errorf("Expected docs page, got %d", docs.StatusCode)
+	}
+}
+
+func TestApp_RequestValidation(t *testing.T) {
+	app, err := NewApp("")
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	server := httptest.NewServer(app.server.Handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/check-links", "application/json", bytes.NewReader([]byte(`{"links": "example.com"}`)))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", resp.StatusCode)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Expected problem+json, got %s", contentType)
+	}
+
+	var validation struct {
+		Type      string `json:"type"`
+		RequestID string `json:"request_id"`
+		Errors    []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&validation); err != nil {
+		t.Fatalf("Failed to decode validation error: %v", err)
+	}
+
+	if validation.RequestID == "" || validation.RequestID != resp.Header.Get("X-Request-ID") {
+		t.Errorf("Expected request ID to match header, got %q", validation.RequestID)
+	}
+
+	if len(validation.Errors) != 1 || validation.Errors[0].Field != "links" {
+		t.Errorf("Expected error for links field, got %+v", validation)
+	}
+}
+
+func TestApp_EmptyLinksRejectedConsistently(t *testing.T) {
+	handler, err := bootstrapHandler(config.Default())
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+
+	for _, path := range []string{"/api/check-links", "/api/v2/check-links"} {
+		for _, body := range []string{`{}`, `{"links": []}`} {
+			req := httptest.NewRequest("POST", path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			var p struct {
+				Type   string `json:"type"`
+				Errors []struct {
+					Field string `json:"field"`
+				} `json:"errors"`
+			}
+			json.NewDecoder(w.Body).Decode(&p)
+			if w.Code != http.StatusBadRequest || p.Type != "/problems/validation-error" || len(p.Errors) != 1 || p.Errors[0].Field != "links" {
+				t.Errorf("%s %s: expected 400 validation error for links, got %d %+v", path, body, w.Code, p)
+			}
+		}
+	}
+}
+
+func TestBootstrapHandler_InvalidCABundle(t *testing.T) {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write CA bundle: %v", err)
+	}
+	cfg.Checker.HTTP.CABundle = path
+
+	if _, err := bootstrapHandler(cfg); err == nil || !strings.Contains(err.Error(), "checker.http") {
+		t.Errorf("Expected CA bundle error naming the key, got %v", err)
+	}
+}
+
+func TestApp_APIKeyAuth(t *testing.T) {
+	cfg := config.Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.Keys = []config.KeyConfig{
+		{Name: "bootstrap", Hash: keyservice.HashKey("admin-secret"), Scopes: []string{"admin"}},
+	}
+
+	handler, err := bootstrapHandler(cfg)
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	do := func(method, path, key, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		return resp
+	}
+	checkLinks := `{"links":["http://127.0.0.1/"]}`
+
+	resp := do("POST", "/api/check-links", "", checkLinks)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+		t.Fatalf("Expected 401 with challenge, got %d", resp.StatusCode)
+	}
+
+	resp = do("POST", "/api/admin/keys", "admin-secret", `{"name":"ci","scopes":["check:write"]}`)
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || created.Key == "" {
+		t.Fatalf("Expected key to be created, got %d", resp.StatusCode)
+	}
+
+	resp = do("POST", "/api/check-links", created.Key, checkLinks)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected check with check:write key to succeed, got %d", resp.StatusCode)
+	}
+
+	resp = do("POST", "/api/generate-report", created.Key, `{"links_list":[1]}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected report without report:read to be forbidden, got %d", resp.StatusCode)
+	}
+
+	resp = do("GET", "/api/admin/keys", created.Key, "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected admin endpoints to require admin scope, got %d", resp.StatusCode)
+	}
+
+	resp = do("DELETE", "/api/admin/keys/"+created.ID, "admin-secret", "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected key to be revoked, got %d", resp.StatusCode)
+	}
+
+	resp = do("POST", "/api/check-links", created.Key, checkLinks)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected revoked key to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestApp_JWTAuth(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": "k1",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}}})
+	}))
+	defer jwks.Close()
+
+	cfg := config.Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.JWT.JWKSURL = jwks.URL
+	cfg.Auth.JWT.Issuer = "https://idp.example.com/"
+	cfg.Auth.JWT.Audience = "link-checker"
+	cfg.Auth.JWT.ScopeMap = map[string]string{"links.check": "check:write"}
+
+	handler, err := bootstrapHandler(cfg)
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss":   "https://idp.example.com/",
+		"aud":   "link-checker",
+		"sub":   "ci",
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"scope": "links.check",
+	})
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		body   string
+		token  string
+		status int
+	}{
+		{"/api/check-links", `{"links":["http://127.0.0.1/"]}`, signed, http.StatusOK},
+		{"/api/check-links", `{"links":["http://127.0.0.1/"]}`, signed[:len(signed)-4] + "AAAA", http.StatusUnauthorized},
+		{"/api/generate-report", `{"links_list":[1]}`, signed, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, w.Code)
+		}
+	}
+}
+
+func TestApp_TenantSettings(t *testing.T) {
+	cfg := config.Default()
+	cfg.API.MaxLinks = 1
+	cfg.API.MaxBodyBytes = 100
+	cfg.Auth.Enabled = true
+	cfg.Auth.Keys = []config.KeyConfig{
+		{Name: "team-a", Tenant: "team-a", Hash: keyservice.HashKey("team-a-secret"), Scopes: []string{"check:write"}},
+		{Name: "shared", Hash: keyservice.HashKey("shared-secret"), Scopes: []string{"check:write"}},
+	}
+	cfg.Tenants = []config.TenantConfig{{Name: "team-a", API: config.APIConfig{MaxLinks: 5, MaxBodyBytes: 1 << 10}}}
+
+	handler, err := bootstrapHandler(cfg)
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+
+	twoLinks := `{"links":["http://127.0.0.1/a","http://127.0.0.1/b"]}`
+	largeBody := `{"links":["http://127.0.0.1/` + strings.Repeat("a", 100) + `"]}`
+	tests := []struct {
+		key    string
+		body   string
+		status int
+	}{
+		{"team-a-secret", twoLinks, http.StatusOK},
+		{"shared-secret", twoLinks, http.StatusUnprocessableEntity},
+		{"team-a-secret", largeBody, http.StatusOK},
+		{"shared-secret", largeBody, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/api/check-links", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", tt.key)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s with %d bytes: expected status %d, got %d", tt.key, len(tt.body), tt.status, w.Code)
+		}
+	}
+}
+
+func TestApp_Quota(t *testing.T) {
+	cfg := config.Default()
+	cfg.Quota.ChecksPerHour = 2
+
+	handler, err := bootstrapHandler(cfg)
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	checkLinks := `{"links":["http://127.0.0.1/a","http://127.0.0.1/b"]}`
+
+	if w := do("POST", "/api/check-links", checkLinks); w.Code != http.StatusOK {
+		t.Fatalf("Expected first batch to be admitted, got %d", w.Code)
+	}
+
+	w := do("POST", "/api/check-links", checkLinks)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 once the hourly quota is used, got %d", w.Code)
+	}
+	if retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retryAfter < 1 || retryAfter > 3600 {
+		t.Errorf("Expected Retry-After until the next hour, got %q", w.Header().Get("Retry-After"))
+	}
+
+	w = do("GET", "/api/usage?hours=1", "")
+	var usage struct {
+		Tenant struct {
+			Subject string `json:"subject"`
+			History []struct {
+				Checks   int `json:"checks"`
+				Rejected int `json:"rejected"`
+			} `json:"history"`
+		} `json:"tenant"`
+	}
+	json.NewDecoder(w.Body).Decode(&usage)
+	if w.Code != http.StatusOK || usage.Tenant.Subject != "tenant:default" {
+		t.Fatalf("Unexpected usage response %d: %+v", w.Code, usage)
+	}
+	if history := usage.Tenant.History; len(history) != 1 || history[0].Checks != 2 || history[0].Rejected != 1 {
+		t.Errorf("Unexpected usage history: %+v", history)
+	}
+}
+
+func TestApp_RateLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Burst = 2
+	cfg.RateLimit.RefillPeriod = time.Hour
+
+	repos, err := newRepositories(cfg)
+	if err != nil {
+		t.Fatalf("newRepositories failed: %v", err)
+	}
+	svc, err := newServices(cfg, repos)
+	if err != nil {
+		t.Fatalf("newServices failed: %v", err)
+	}
+	handler, err := buildHandler(cfg, svc)
+	if err != nil {
+		t.Fatalf("buildHandler failed: %v", err)
+	}
+
+	do := func(handler http.Handler) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(handler)
+	if w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("Expected 200 with one request remaining, got %d %q", w.Code, w.Header().Get("RateLimit-Remaining"))
+	}
+
+	// The buckets outlive a reload.
+	handler, err = buildHandler(cfg, svc)
+	if err != nil {
+		t.Fatalf("buildHandler failed: %v", err)
+	}
+	do(handler)
+
+	w = do(handler)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 once the bucket is empty, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected problem content type, got %q", ct)
+	}
+	if retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retryAfter < 3500 || retryAfter > 3600 {
+		t.Errorf("Expected Retry-After of about an hour, got %q", w.Header().Get("Retry-After"))
+	}
+}
+
+type recordingRateLimitStore struct {
+	middlewares.RateLimitStore
+	keys []string
+}
+
+func (s *recordingRateLimitStore) Take(key string, limit middlewares.RateLimit, now time.Time) middlewares.RateLimitResult {
+	s.keys = append(s.keys, key)
+	return s.RateLimitStore.Take(key, limit, now)
+}
+
+func TestApp_RateLimitKeys(t *testing.T) {
+	cfg := config.Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.Keys = []config.KeyConfig{
+		{Name: "a", Hash: keyservice.HashKey("a-secret"), Scopes: []string{"admin"}},
+		{Name: "b", Hash: keyservice.HashKey("b-secret"), Scopes: []string{"admin"}},
+	}
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Burst = 1
+	cfg.RateLimit.RefillPeriod = time.Hour
+
+	do := func(handler http.Handler, method, path, key string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("api_key", func(t *testing.T) {
+		cfg.RateLimit.Key = "api_key"
+		repos, _ := newRepositories(cfg)
+		store := &recordingRateLimitStore{RateLimitStore: repos.rateLimits}
+		repos.rateLimits = store
+		svc, _ := newServices(cfg, repos)
+		handler, err := buildHandler(cfg, svc)
+		if err != nil {
+			t.Fatalf("buildHandler failed: %v", err)
+		}
+
+		if code := do(handler, "GET", "/api/admin/keys", "junk-1"); code != http.StatusUnauthorized {
+			t.Fatalf("Expected 401 for unknown keys, got %d", code)
+		}
+		if len(store.keys) != 1 || store.keys[0] != "ip:192.0.2.1" {
+			t.Errorf("Expected unknown keys to be charged to the client IP, got %v", store.keys)
+		}
+
+		if code := do(handler, "GET", "/api/admin/keys", "a-secret"); code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", code)
+		}
+		if code := do(handler, "GET", "/api/admin/keys", "a-secret"); code != http.StatusTooManyRequests {
+			t.Errorf("Expected 429 once the bucket of the key is empty, got %d", code)
+		}
+		if code := do(handler, "GET", "/api/admin/keys", "b-secret"); code != http.StatusOK {
+			t.Errorf("Expected other keys to have their own bucket, got %d", code)
+		}
+		if code := do(handler, "GET", "/openapi.json", ""); code != http.StatusTooManyRequests {
+			t.Errorf("Expected anonymous requests to share the IP bucket of rejected keys, got %d", code)
+		}
+	})
+
+	t.Run("route", func(t *testing.T) {
+		cfg.RateLimit.Key = "route"
+		repos, _ := newRepositories(cfg)
+		svc, _ := newServices(cfg, repos)
+		handler, err := buildHandler(cfg, svc)
+		if err != nil {
+			t.Fatalf("buildHandler failed: %v", err)
+		}
+
+		do(handler, "DELETE", "/api/admin/keys/one", "a-secret")
+		if code := do(handler, "DELETE", "/api/admin/keys/two", "b-secret"); code != http.StatusTooManyRequests {
+			t.Errorf("Expected paths of one route to share a bucket, got %d", code)
+		}
+		if code := do(handler, "GET", "/api/admin/keys", "a-secret"); code != http.StatusOK {
+			t.Errorf("Expected other routes to have their own bucket, got %d", code)
+		}
+	})
+}
+
+func TestApp_RateLimitsFailedAuthentication(t *testing.T) {
+	for _, key := range []string{"ip", "route", "api_key"} {
+		cfg := config.Default()
+		cfg.Auth.Enabled = true
+		cfg.RateLimit.Enabled = true
+		cfg.RateLimit.Key = key
+		cfg.RateLimit.Burst = 3
+		cfg.RateLimit.RefillPeriod = time.Hour
+
+		handler, err := bootstrapHandler(cfg)
+		if err != nil {
+			t.Fatalf("bootstrapHandler failed: %v", err)
+		}
+
+		codes := make([]int, 4)
+		for i := range codes {
+			req := httptest.NewRequest("GET", "/api/usage", nil)
+			req.Header.Set("X-API-Key", "junk")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			codes[i] = w.Code
+		}
+
+		if codes[0] != http.StatusUnauthorized || codes[3] != http.StatusTooManyRequests {
+			t.Errorf("%s: expected repeated 401s to end in 429, got %v", key, codes)
+		}
+	}
+}
+
+func TestApp_V2BatchIDs(t *testing.T) {
+	handler, err := bootstrapHandler(config.Default())
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+
+	do := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	var v1 struct {
+		LinksNum int `json:"links_num"`
+	}
+	json.NewDecoder(do("/api/check-links", `{"links":["http://127.0.0.1/"]}`).Body).Decode(&v1)
+	var v2 struct {
+		BatchID string `json:"batch_id"`
+	}
+	json.NewDecoder(do("/api/v2/check-links", `{"links":["http://127.0.0.1/"]}`).Body).Decode(&v2)
+	if v1.LinksNum != 1 || v2.BatchID == "" {
+		t.Fatalf("Expected batch number 1 and an opaque ID, got %d and %q", v1.LinksNum, v2.BatchID)
+	}
+
+	if w := do("/api/generate-report", `{"links_list":[1, 2]}`); w.Code != http.StatusOK {
+		t.Errorf("Expected v1 report by batch numbers, got %d", w.Code)
+	}
+	if w := do("/api/v2/generate-report", `{"links_list":["`+v2.BatchID+`"]}`); w.Code != http.StatusOK {
+		t.Errorf("Expected v2 report by batch ID, got %d", w.Code)
+	}
+}
+
+func TestApp_ValidatesAfterAuthentication(t *testing.T) {
+	cfg := config.Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.Keys = []config.KeyConfig{
+		{Name: "ci", Hash: keyservice.HashKey("ci-secret"), Scopes: []string{"check:write"}},
+	}
+
+	handler, err := bootstrapHandler(cfg)
+	if err != nil {
+		t.Fatalf("bootstrapHandler failed: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"ci-secret", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/api/check-links", strings.NewReader(`{"links":[1]}`))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		var p struct {
+			Errors []any `json:"errors"`
+		}
+		json.NewDecoder(w.Body).Decode(&p)
+		if w.Code != tt.status {
+			t.Errorf("Key %q: expected status %d, got %d", tt.key, tt.status, w.Code)
+		}
+		if tt.key == "" && len(p.Errors) > 0 {
+			t.Errorf("Expected no schema details for anonymous callers, got %v", p.Errors)
+		}
+	}
+}
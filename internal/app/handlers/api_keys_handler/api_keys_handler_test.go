@@ -0,0 +1,191 @@
+package api_keys_handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+type mockKeyService struct {
+	keys    []*model.APIKey
+	scopes  []model.Scope
+	tenant  string
+	quota   model.Quota
+	revoked string
+	err     error
+}
+
+func (m *mockKeyService) Create(name, tenantID string, scopes []model.Scope, quota model.Quota) (*model.APIKey, string, error) {
+	m.scopes = scopes
+	m.tenant = tenantID
+	m.quota = quota
+	if m.err != nil {
+		return nil, "", m.err
+	}
+	return &model.APIKey{ID: "k1", Name: name, Tenant: tenantID, Quota: quota, Prefix: "lck_abcdefgh", Scopes: scopes, CreatedAt: time.Now()}, "lck_abcdefghsecret", nil
+}
+
+func (m *mockKeyService) List(ctx context.Context) ([]*model.APIKey, error) {
+	m.tenant = tenant.FromContext(ctx)
+	return m.keys, m.err
+}
+
+func (m *mockKeyService) Revoke(ctx context.Context, id string) error {
+	m.tenant = tenant.FromContext(ctx)
+	m.revoked = id
+	return m.err
+}
+
+func (m *mockKeyService) Authenticate(ctx context.Context, secret string) (*model.Principal, error) {
+	return nil, nil
+}
+
+func (m *mockKeyService) Configure(opts ...service.Option) {}
+
+func TestCreateKeyHandler(t *testing.T) {
+	mock := &mockKeyService{}
+	handler := NewCreateKeyHandler(mock)
+
+	body := []byte(`{"name":"ci","scopes":["check:write","report:read"],"quota":{"links_per_batch":50}}`)
+	req := httptest.NewRequest("POST", "/api/admin/keys", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", w.Code)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Error("Expected key response not to be cached")
+	}
+	if len(mock.scopes) != 2 || mock.scopes[1] != model.ScopeReportRead {
+		t.Errorf("Expected scopes to be passed, got %v", mock.scopes)
+	}
+
+	var resp CreateKeyResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.ID != "k1" || resp.Key != "lck_abcdefghsecret" || resp.Prefix != "lck_abcdefgh" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if mock.quota.LinksPerBatch != 50 || resp.Quota == nil || resp.Quota.LinksPerBatch != 50 {
+		t.Errorf("Expected quota to be passed and returned, got %+v and %+v", mock.quota, resp.Quota)
+	}
+}
+
+func TestCreateKeyHandler_Tenant(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+		tenant string
+	}{
+		{`{"name":"ci","scopes":["check:write"]}`, http.StatusCreated, "team-a"},
+		{`{"name":"ci","tenant":"team-a","scopes":["check:write"]}`, http.StatusCreated, "team-a"},
+		{`{"name":"ci","tenant":"team-b","scopes":["check:write"]}`, http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		mock := &mockKeyService{}
+		handler := NewCreateKeyHandler(mock)
+
+		req := httptest.NewRequest("POST", "/api/admin/keys", bytes.NewReader([]byte(tt.body)))
+		req = req.WithContext(tenant.WithTenant(req.Context(), "team-a"))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.body, tt.status, w.Code)
+		}
+		if mock.tenant != tt.tenant {
+			t.Errorf("%s: expected key for tenant %q, got %q", tt.body, tt.tenant, mock.tenant)
+		}
+	}
+}
+
+func TestCreateKeyHandler_InvalidScope(t *testing.T) {
+	handler := NewCreateKeyHandler(&mockKeyService{err: service.ErrInvalidScope})
+
+	req := httptest.NewRequest("POST", "/api/admin/keys", bytes.NewReader([]byte(`{"scopes":["everything"]}`)))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Errorf("Expected problem response, got %s", ct)
+	}
+}
+
+func TestListKeysHandler(t *testing.T) {
+	revokedAt := time.Now()
+	mock := &mockKeyService{keys: []*model.APIKey{
+		{ID: "config-bootstrap", Name: "bootstrap", Hash: "h1", Scopes: []model.Scope{model.ScopeAdmin}, Static: true},
+		{ID: "k2", Name: "old", Prefix: "lck_zzzzzzzz", Hash: "h2", Scopes: []model.Scope{model.ScopeCheckWrite}, RevokedAt: revokedAt},
+	}}
+	handler := NewListKeysHandler(mock)
+
+	req := httptest.NewRequest("GET", "/api/admin/keys", nil)
+	req = req.WithContext(tenant.WithTenant(req.Context(), "team-a"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if mock.tenant != "team-a" {
+		t.Errorf("Expected keys of the caller's tenant to be listed, got %q", mock.tenant)
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("h2")) {
+		t.Error("Expected key hashes not to be returned")
+	}
+
+	var resp ListKeysResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Keys) != 2 || !resp.Keys[0].Static || resp.Keys[1].RevokedAt == nil {
+		t.Errorf("Unexpected keys: %+v", resp.Keys)
+	}
+}
+
+func TestRevokeKeyHandler(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusNoContent},
+		{service.ErrKeyNotFound, http.StatusNotFound},
+		{service.ErrStaticKey, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		mock := &mockKeyService{err: tt.err}
+		mx := http.NewServeMux()
+		mx.Handle("DELETE /api/admin/keys/{id}", NewRevokeKeyHandler(mock))
+
+		req := httptest.NewRequest("DELETE", "/api/admin/keys/k1", nil)
+		req = req.WithContext(tenant.WithTenant(req.Context(), "team-a"))
+		w := httptest.NewRecorder()
+		mx.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("Expected status %d for %v, got %d", tt.status, tt.err, w.Code)
+		}
+		if mock.revoked != "k1" || mock.tenant != "team-a" {
+			t.Errorf("Expected key k1 of team-a to be revoked, got %q of %q", mock.revoked, mock.tenant)
+		}
+	}
+}
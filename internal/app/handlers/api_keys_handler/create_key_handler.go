@@ -0,0 +1,66 @@
+package api_keys_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+type KeyCreator interface {
+	Create(name, tenantID string, scopes []model.Scope, quota model.Quota) (*model.APIKey, string, error)
+}
+
+type CreateKeyHandler struct {
+	keyService KeyCreator
+}
+
+func NewCreateKeyHandler(keyService service.KeyService) *CreateKeyHandler {
+	return &CreateKeyHandler{
+		keyService: keyService,
+	}
+}
+
+func (h *CreateKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var req CreateKeyRequest
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Invalid JSON in create-key request: %v", err)
+		problem.Write(w, r, problem.FromDecodeError(err))
+		return
+	}
+
+	scopes := make([]model.Scope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = model.Scope(scope)
+	}
+
+	// Administrators manage the keys of their own tenant only.
+	tenantID := tenant.FromContext(r.Context())
+	if req.Tenant != "" && req.Tenant != tenantID {
+		log.Printf("Rejected create-key request for tenant %s from tenant %s", req.Tenant, tenantID)
+		problem.Write(w, r, problem.New(http.StatusForbidden, problem.TypeForbidden, "Keys can only be created for your own tenant"))
+		return
+	}
+
+	key, secret, err := h.keyService.Create(req.Name, tenantID, scopes, req.Quota.quota())
+	if err != nil {
+		log.Printf("Error creating API key: %v", err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateKeyResponse{
+		KeyResponse: newKeyResponse(key),
+		Key:         secret,
+	})
+}
@@ -0,0 +1,43 @@
+package api_keys_handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type KeyLister interface {
+	List(ctx context.Context) ([]*model.APIKey, error)
+}
+
+type ListKeysHandler struct {
+	keyService KeyLister
+}
+
+func NewListKeysHandler(keyService service.KeyService) *ListKeysHandler {
+	return &ListKeysHandler{
+		keyService: keyService,
+	}
+}
+
+func (h *ListKeysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keyService.List(r.Context())
+	if err != nil {
+		log.Printf("Error listing API keys: %v", err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	resp := ListKeysResponse{Keys: make([]KeyResponse, len(keys))}
+	for i, key := range keys {
+		resp.Keys[i] = newKeyResponse(key)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,28 @@
+package api_keys_handler
+
+import "github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+
+type CreateKeyRequest struct {
+	Name   string   `json:"name"`
+	Tenant string   `json:"tenant,omitempty"`
+	Scopes []string `json:"scopes"`
+	Quota  *Quota   `json:"quota,omitempty"`
+}
+
+// Quota limits a single key; stored batches are limited per tenant only.
+type Quota struct {
+	ChecksPerHour     int `json:"checks_per_hour,omitempty"`
+	LinksPerBatch     int `json:"links_per_batch,omitempty"`
+	ConcurrentBatches int `json:"concurrent_batches,omitempty"`
+}
+
+func (q *Quota) quota() model.Quota {
+	if q == nil {
+		return model.Quota{}
+	}
+	return model.Quota{
+		ChecksPerHour:     q.ChecksPerHour,
+		LinksPerBatch:     q.LinksPerBatch,
+		ConcurrentBatches: q.ConcurrentBatches,
+	}
+}
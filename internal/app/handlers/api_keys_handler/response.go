@@ -0,0 +1,60 @@
+package api_keys_handler
+
+import (
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type KeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Tenant     string     `json:"tenant"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	Quota      *Quota     `json:"quota,omitempty"`
+	Static     bool       `json:"static,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateKeyResponse struct {
+	KeyResponse
+	Key string `json:"key"`
+}
+
+type ListKeysResponse struct {
+	Keys []KeyResponse `json:"keys"`
+}
+
+func newKeyResponse(key *model.APIKey) KeyResponse {
+	resp := KeyResponse{
+		ID:     key.ID,
+		Name:   key.Name,
+		Tenant: key.Tenant,
+		Prefix: key.Prefix,
+		Scopes: make([]string, len(key.Scopes)),
+		Static: key.Static,
+	}
+	for i, scope := range key.Scopes {
+		resp.Scopes[i] = string(scope)
+	}
+	if key.Quota != (model.Quota{}) {
+		resp.Quota = &Quota{
+			ChecksPerHour:     key.Quota.ChecksPerHour,
+			LinksPerBatch:     key.Quota.LinksPerBatch,
+			ConcurrentBatches: key.Quota.ConcurrentBatches,
+		}
+	}
+	if !key.CreatedAt.IsZero() {
+		resp.CreatedAt = &key.CreatedAt
+	}
+	if !key.LastUsedAt.IsZero() {
+		resp.LastUsedAt = &key.LastUsedAt
+	}
+	if key.Revoked() {
+		resp.RevokedAt = &key.RevokedAt
+	}
+	return resp
+}
@@ -0,0 +1,35 @@
+package api_keys_handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+)
+
+type KeyRevoker interface {
+	Revoke(ctx context.Context, id string) error
+}
+
+type RevokeKeyHandler struct {
+	keyService KeyRevoker
+}
+
+func NewRevokeKeyHandler(keyService service.KeyService) *RevokeKeyHandler {
+	return &RevokeKeyHandler{
+		keyService: keyService,
+	}
+}
+
+func (h *RevokeKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.keyService.Revoke(r.Context(), id); err != nil {
+		log.Printf("Error revoking API key %s: %v", id, err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
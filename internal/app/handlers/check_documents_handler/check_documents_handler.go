@@ -0,0 +1,135 @@
+package check_documents_handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/check_links_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/extractor"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+const maxUploadSize = 32 << 20
+
+type LinkService interface {
+	CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error)
+}
+
+type CheckDocumentsHandler struct {
+	linkService LinkService
+	limits      check_links_handler.LimitSet
+}
+
+// NewCheckDocumentsHandler applies the check-links limits to the links found
+// in the uploaded documents; the upload itself may be larger.
+func NewCheckDocumentsHandler(linkService service.LinkService, opts ...check_links_handler.Option) *CheckDocumentsHandler {
+	return &CheckDocumentsHandler{
+		linkService: linkService,
+		limits:      check_links_handler.NewLimitSet(opts...),
+	}
+}
+
+type documentLink struct {
+	file string
+	extractor.DocumentLink
+}
+
+func (h *CheckDocumentsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		log.Printf("Invalid multipart body in check-documents request: %v", err)
+		problem.Write(w, r, problem.New(http.StatusBadRequest, problem.TypeInvalidBody, "Expected multipart/form-data body"))
+		return
+	}
+
+	var found []documentLink
+	var urls []string
+	var opts []service.CheckOption
+	seen := make(map[string]bool)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to read multipart body: %v", err)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.TypeInvalidBody, "Invalid multipart body"))
+			return
+		}
+		if part.FileName() == "" {
+			if part.FormName() == "validate_anchors" {
+				value, _ := io.ReadAll(io.LimitReader(part, 16))
+				if string(value) == "true" {
+					opts = append(opts, service.WithAnchorValidation())
+				}
+			}
+			continue
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			log.Printf("Failed to read uploaded file %s: %v", part.FileName(), err)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.TypeInvalidBody, "Invalid multipart body"))
+			return
+		}
+
+		for _, link := range extractor.ExtractDocumentLinks(part.FileName(), content) {
+			found = append(found, documentLink{file: part.FileName(), DocumentLink: link})
+			if !seen[link.URL] {
+				seen[link.URL] = true
+				urls = append(urls, link.URL)
+			}
+		}
+	}
+
+	if len(urls) == 0 {
+		p := problem.New(http.StatusUnprocessableEntity, problem.TypeValidation, "No links found in the uploaded documents")
+		p.Errors = []problem.FieldError{{Field: "files", Message: "must contain at least one link"}}
+		problem.Write(w, r, p)
+		return
+	}
+	if p := h.limits.ForTenant(tenant.FromContext(r.Context())).CheckLinks("links", urls); p != nil {
+		log.Printf("Rejected check-documents request: %s", p.Detail)
+		problem.Write(w, r, p)
+		return
+	}
+
+	log.Printf("Checking %d links found in uploaded documents", len(urls))
+	batch, err := h.linkService.CheckLinks(r.Context(), urls, opts...)
+	if err != nil {
+		log.Printf("Error checking links: %v", err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("Created batch %s with %d links", batch.ID, len(batch.Links))
+
+	statuses := make(map[string]string)
+	for _, link := range batch.Links {
+		statuses[link.URL] = string(link.Status)
+	}
+
+	resp := CheckDocumentsResponse{
+		BatchID: batch.ID,
+		Results: make([]LinkResult, len(found)),
+	}
+
+	for i, link := range found {
+		resp.Results[i] = LinkResult{
+			File:   link.file,
+			Line:   link.Line,
+			URL:    link.URL,
+			Status: statuses[link.URL],
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,154 @@
+package check_documents_handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/handlers/check_links_handler"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type mockLinkService struct {
+	urls []string
+}
+
+func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error) {
+	m.urls = urls
+	batch := &model.LinkBatch{ID: "b7"}
+	for _, url := range urls {
+		status := model.StatusAvailable
+		if url == "https://example.com/gone" {
+			status = model.StatusNotAvailable
+		}
+		batch.Links = append(batch.Links, model.LinkCheck{URL: url, Status: status})
+	}
+	return batch, nil
+}
+
+func (m *mockLinkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func (m *mockLinkService) GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func TestCheckDocumentsHandler_ServeHTTP(t *testing.T) {
+	mock := &mockLinkService{}
+	handler := NewCheckDocumentsHandler(mock)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("files", "README.md")
+	part.Write([]byte("intro\n[docs](https://example.com/docs)\nsee https://example.com/gone\n"))
+	part, _ = writer.CreateFormFile("files", "notes.txt")
+	part.Write([]byte("https://example.com/docs\n"))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/check-documents", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	if len(mock.urls) != 2 {
+		t.Errorf("Expected 2 unique URLs to be checked, got %v", mock.urls)
+	}
+
+	var resp CheckDocumentsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := []LinkResult{
+		{File: "README.md", Line: 2, URL: "https://example.com/docs", Status: "available"},
+		{File: "README.md", Line: 3, URL: "https://example.com/gone", Status: "not available"},
+		{File: "notes.txt", Line: 1, URL: "https://example.com/docs", Status: "available"},
+	}
+	if resp.BatchID != "b7" || len(resp.Results) != len(expected) {
+		t.Fatalf("Unexpected response: %+v", resp)
+	}
+	for i, result := range resp.Results {
+		if result != expected[i] {
+			t.Errorf("Expected result %d to be %+v, got %+v", i, expected[i], result)
+		}
+	}
+}
+
+func TestCheckDocumentsHandler_NotMultipart(t *testing.T) {
+	handler := NewCheckDocumentsHandler(&mockLinkService{})
+
+	req := httptest.NewRequest("POST", "/api/check-documents", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestCheckDocumentsHandler_Limits(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantType string
+	}{
+		{"no links", "nothing to see here\n", "/problems/validation-error"},
+		{"too many links", "https://example.com/a\nhttps://example.com/b\nhttps://example.com/c\n", "/problems/limit-exceeded"},
+		{"long URL", "https://example.com/" + strings.Repeat("x", 40) + "\n", "/problems/limit-exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLinkService{}
+			handler := NewCheckDocumentsHandler(mock, check_links_handler.WithLimits(check_links_handler.Limits{MaxLinks: 2, MaxURLLength: 40}))
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, _ := writer.CreateFormFile("files", "notes.txt")
+			part.Write([]byte(tt.content))
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/api/check-documents", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("Expected status 422, got %d", w.Code)
+			}
+			var p struct {
+				Type string `json:"type"`
+			}
+			json.NewDecoder(w.Body).Decode(&p)
+			if p.Type != tt.wantType {
+				t.Errorf("Expected problem type %s, got %s", tt.wantType, p.Type)
+			}
+			if mock.urls != nil {
+				t.Error("Expected no links to be checked")
+			}
+		})
+	}
+}
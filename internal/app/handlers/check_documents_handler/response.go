@@ -0,0 +1,13 @@
+package check_documents_handler
+
+type CheckDocumentsResponse struct {
+	BatchID string       `json:"batch_id"`
+	Results []LinkResult `json:"results"`
+}
+
+type LinkResult struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
@@ -6,51 +6,136 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
 )
 
 type LinkService interface {
-	CheckLinks(ctx context.Context, urls []string) (*model.LinkBatch, error)
+	CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error)
 }
 
 type CheckLinksHandler struct {
 	linkService LinkService
+	limits      LimitSet
 }
 
-func NewCheckLinksHandler(linkService service.LinkService) *CheckLinksHandler {
+func NewCheckLinksHandler(linkService service.LinkService, opts ...Option) *CheckLinksHandler {
 	return &CheckLinksHandler{
 		linkService: linkService,
+		limits:      NewLimitSet(opts...),
 	}
 }
 
 func (h *CheckLinksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var req CheckLinksRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Invalid JSON in check-links request: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
-		return
-	}
-
-	log.Printf("Checking %d links", len(req.Links))
-	batch, err := h.linkService.CheckLinks(r.Context(), req.Links)
-	if err != nil {
-		log.Printf("Error checking links: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	batch := checkLinks(h.linkService, h.limits, w, r)
+	if batch == nil {
 		return
 	}
 
-	log.Printf("Created batch %d with %d links", batch.ID, len(batch.Links))
-
 	resp := CheckLinksResponse{
 		Links:    make(map[string]string),
-		LinksNum: batch.ID,
+		LinksNum: batch.Number,
+		Security: newSecurityReport(batch),
 	}
 
 	for _, link := range batch.Links {
 		resp.Links[link.URL] = string(link.Status)
+		if link.Status == model.StatusInvalid || link.Status == model.StatusBlocked {
+			if resp.Errors == nil {
+				resp.Errors = make(map[string]string)
+			}
+			resp.Errors[link.URL] = link.Error
+		}
+		if len(link.FailedAssertions) > 0 {
+			if resp.AssertionFailures == nil {
+				resp.AssertionFailures = make(map[string][]string)
+			}
+			resp.AssertionFailures[link.URL] = link.FailedAssertions
+		}
+		if link.Status == model.StatusSoft404 {
+			if resp.Soft404 == nil {
+				resp.Soft404 = make(map[string]float64)
+			}
+			resp.Soft404[link.URL] = link.Soft404Confidence
+		}
+		if link.TLS != nil {
+			if resp.Certificates == nil {
+				resp.Certificates = make(map[string]Certificate)
+			}
+			resp.Certificates[link.URL] = newCertificate(link.TLS)
+		}
+		if link.Scheme != "" {
+			if resp.Schemes == nil {
+				resp.Schemes = make(map[string]string)
+			}
+			resp.Schemes[link.URL] = link.Scheme
+		}
+		if link.InsecureOnly {
+			resp.InsecureOnly = append(resp.InsecureOnly, link.URL)
+		}
+		if link.Proxy != "" {
+			if resp.Proxies == nil {
+				resp.Proxies = make(map[string]string)
+			}
+			resp.Proxies[link.URL] = link.Proxy
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+func checkLinks(linkService LinkService, limitSet LimitSet, w http.ResponseWriter, r *http.Request) *model.LinkBatch {
+	limits := limitSet.ForTenant(tenant.FromContext(r.Context()))
+	r.Body = http.MaxBytesReader(w, r.Body, limits.MaxBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var req CheckLinksRequest
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Invalid JSON in check-links request: %v", err)
+		problem.Write(w, r, problem.FromDecodeError(err))
+		return nil
+	}
+
+	if p := limits.check(req); p != nil {
+		log.Printf("Rejected check-links request: %s", p.Detail)
+		problem.Write(w, r, p)
+		return nil
+	}
+
+	var opts []service.CheckOption
+	if req.ValidateAnchors {
+		opts = append(opts, service.WithAnchorValidation())
+	}
+	if req.DetectSoft404 {
+		opts = append(opts, service.WithSoft404Detection())
+	}
+	if req.SecurityAudit {
+		opts = append(opts, service.WithSecurityAudit())
+	}
+	if assertions := req.assertions(); assertions != nil {
+		opts = append(opts, service.WithAssertions(assertions))
+	}
+	if auth := req.auth(); auth != nil {
+		opts = append(opts, service.WithLinkAuth(auth))
+	}
+	if rules := req.hostAuth(); rules != nil {
+		opts = append(opts, service.WithHostAuth(rules))
+	}
+
+	log.Printf("Checking %d links", len(req.Links))
+	batch, err := linkService.CheckLinks(r.Context(), req.Links, opts...)
+	if err != nil {
+		log.Printf("Error checking links: %v", err)
+		problem.WriteError(w, r, err)
+		return nil
+	}
+
+	log.Printf("Created batch %s with %d links", batch.ID, len(batch.Links))
+
+	return batch
+}
@@ -4,25 +4,54 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
 )
 
-type mockLinkService struct{}
+type mockLinkService struct {
+	opts  []service.CheckOption
+	err   error
+	batch *model.LinkBatch
+}
 
-func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string) (*model.LinkBatch, error) {
+func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error) {
+	m.opts = opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.batch != nil {
+		return m.batch, nil
+	}
 	return &model.LinkBatch{
-		ID: 1,
+		ID:     "b1",
+		Number: 1,
 		Links: []model.LinkCheck{
 			{URL: "google.com", Status: model.StatusAvailable},
 		},
 	}, nil
 }
 
-func (m *mockLinkService) GenerateReport(batchIDs []int) ([]byte, error) {
+func (m *mockLinkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func (m *mockLinkService) GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error) {
 	return []byte("fake pdf"), nil
 }
 
@@ -56,4 +85,151 @@ func TestCheckLinksHandler_ServeHTTP(t *testing.T) {
 	if resp.Links["google.com"] != "available" {
 		t.Errorf("Expected google.com to be available, got %s", resp.Links["google.com"])
 	}
-}
\ No newline at end of file
+}
+
+func TestCheckLinksHandler_ValidateAnchors(t *testing.T) {
+	mock := &mockLinkService{}
+	handler := NewCheckLinksHandler(mock)
+
+	body := []byte(`{"links":["docs.example.com/page#install"],"validate_anchors":true}`)
+	req := httptest.NewRequest("POST", "/api/check-links", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	if len(mock.opts) != 1 {
+		t.Errorf("Expected anchor validation option to be passed, got %d options", len(mock.opts))
+	}
+}
+
+func TestCheckLinksHandler_InvalidAssertion(t *testing.T) {
+	mock := &mockLinkService{err: fmt.Errorf("%w: unknown type", service.ErrInvalidAssertion)}
+	handler := NewCheckLinksHandler(mock)
+
+	body := []byte(`{"links":["example.com"],"assertions":{"example.com":[{"type":"unknown"}]}}`)
+	req := httptest.NewRequest("POST", "/api/check-links", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+
+	if len(mock.opts) != 1 {
+		t.Error("Expected assertions option to be passed")
+	}
+
+	var p problem.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if p.Type != problem.TypeInvalidAssertion || p.Detail == "" {
+		t.Errorf("Unexpected problem: %+v", p)
+	}
+}
+
+func TestCheckLinksHandler_InvalidAuth(t *testing.T) {
+	mock := &mockLinkService{err: fmt.Errorf("%w for example.com: unknown credential \"nope\"", service.ErrInvalidAuth)}
+	handler := NewCheckLinksHandler(mock)
+
+	body := []byte(`{"links":["example.com"],"auth":{"example.com":{"credential":"nope"}},"host_auth":[{"hosts":["*.corp"],"headers":{"X-Team":"qa"}}]}`)
+	req := httptest.NewRequest("POST", "/api/check-links", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+
+	if len(mock.opts) != 2 {
+		t.Errorf("Expected link and host auth options to be passed, got %d options", len(mock.opts))
+	}
+
+	var p problem.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if p.Type != problem.TypeInvalidAuth {
+		t.Errorf("Unexpected problem: %+v", p)
+	}
+}
+
+func TestCheckLinksHandler_Limits(t *testing.T) {
+	limits := WithLimits(Limits{MaxBodyBytes: 256, MaxLinks: 2, MaxURLLength: 20})
+
+	tests := []struct {
+		name        string
+		body        string
+		status      int
+		problemType string
+		field       string
+	}{
+		{"empty list", `{"links": []}`, http.StatusBadRequest, problem.TypeValidation, "links"},
+		{"missing list", `{}`, http.StatusBadRequest, problem.TypeValidation, "links"},
+		{"too many links", `{"links": ["a.com", "b.com", "c.com"]}`, http.StatusUnprocessableEntity, problem.TypeLimitExceeded, "links"},
+		{"url too long", `{"links": ["a.com", "https://example.com/a/long/path"]}`, http.StatusUnprocessableEntity, problem.TypeLimitExceeded, "links[1]"},
+		{"unknown field", `{"links": ["a.com"], "validate_anchor": true}`, http.StatusBadRequest, problem.TypeValidation, "validate_anchor"},
+		{"body too large", `{"links": ["` + strings.Repeat("a", 300) + `"]}`, http.StatusRequestEntityTooLarge, problem.TypeBodyTooLarge, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLinkService{}
+			handler := NewCheckLinksHandler(mock, limits)
+
+			req := httptest.NewRequest("POST", "/api/check-links", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+
+			var p problem.Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+			if p.Type != tt.problemType {
+				t.Errorf("Expected problem type %s, got %s", tt.problemType, p.Type)
+			}
+			if tt.field != "" && (len(p.Errors) == 0 || p.Errors[0].Field != tt.field) {
+				t.Errorf("Expected error for field %s, got %+v", tt.field, p.Errors)
+			}
+		})
+	}
+}
+
+func TestCheckLinksHandler_TenantLimits(t *testing.T) {
+	handler := NewCheckLinksHandler(&mockLinkService{},
+		WithTenantLimits("team-a", Limits{MaxLinks: 3}),
+		WithLimits(Limits{MaxLinks: 1}),
+	)
+	body := `{"links": ["a.com", "b.com"]}`
+
+	tests := []struct {
+		tenant string
+		status int
+	}{
+		{"team-a", http.StatusOK},
+		{"team-b", http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/api/check-links", strings.NewReader(body))
+		req = req.WithContext(tenant.WithTenant(req.Context(), tt.tenant))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.tenant, tt.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package check_links_handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+)
+
+type CheckLinksV2Handler struct {
+	linkService LinkService
+	limits      LimitSet
+}
+
+func NewCheckLinksV2Handler(linkService service.LinkService, opts ...Option) *CheckLinksV2Handler {
+	return &CheckLinksV2Handler{
+		linkService: linkService,
+		limits:      NewLimitSet(opts...),
+	}
+}
+
+func (h *CheckLinksV2Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	batch := checkLinks(h.linkService, h.limits, w, r)
+	if batch == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newCheckLinksV2Response(batch))
+}
@@ -0,0 +1,88 @@
+package check_links_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func TestCheckLinksV2Handler_ServeHTTP(t *testing.T) {
+	createdAt := time.Date(2025, 12, 5, 10, 0, 0, 0, time.UTC)
+	mock := &mockLinkService{
+		batch: &model.LinkBatch{
+			ID:        "b7",
+			CreatedAt: createdAt,
+			Links: []model.LinkCheck{
+				{URL: "b.com", NormalizedURL: "https://b.com/", Status: model.StatusAvailable, StatusCode: 200, Scheme: "https", Proxy: "http://proxy:3128", ResponseTime: 120 * time.Millisecond, CheckedAt: createdAt},
+				{URL: "a.com", NormalizedURL: "http://a.com/", Status: model.StatusNotAvailable, StatusCode: 404, Scheme: "http", InsecureOnly: true},
+				{URL: "B.com", NormalizedURL: "https://b.com/", Status: model.StatusAvailable, StatusCode: 200, Scheme: "https"},
+				{URL: "not a url", Status: model.StatusInvalid, Error: "invalid URL"},
+			},
+		},
+	}
+	handler := NewCheckLinksV2Handler(mock)
+
+	body, _ := json.Marshal(CheckLinksRequest{Links: []string{"b.com", "a.com", "B.com", "not a url"}})
+	req := httptest.NewRequest("POST", "/api/v2/check-links", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var resp CheckLinksV2Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.BatchID != "b7" || !resp.CreatedAt.Equal(createdAt) {
+		t.Errorf("Unexpected batch metadata: %s %v", resp.BatchID, resp.CreatedAt)
+	}
+
+	if resp.Summary.Total != 4 || resp.Summary.Unique != 3 {
+		t.Errorf("Expected 4 total and 3 unique, got %+v", resp.Summary)
+	}
+	if resp.Summary.ByStatus["available"] != 2 || resp.Summary.ByStatus["not available"] != 1 || resp.Summary.ByStatus["invalid"] != 1 {
+		t.Errorf("Unexpected status counts: %v", resp.Summary.ByStatus)
+	}
+
+	if len(resp.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(resp.Results))
+	}
+	for i, url := range []string{"b.com", "a.com", "B.com", "not a url"} {
+		if resp.Results[i].Index != i || resp.Results[i].URL != url {
+			t.Errorf("Expected result %d to be %q, got %+v", i, url, resp.Results[i])
+		}
+	}
+
+	first := resp.Results[0]
+	if first.StatusCode != 200 || first.Scheme != "https" || first.Proxy != "http://proxy:3128" || first.ResponseTimeMs != 120 || !first.CheckedAt.Equal(createdAt) {
+		t.Errorf("Unexpected details for first result: %+v", first)
+	}
+	if !resp.Results[1].InsecureOnly {
+		t.Error("Expected second result to be flagged as insecure only")
+	}
+	if resp.Results[3].Error != "invalid URL" {
+		t.Errorf("Expected validation error, got %q", resp.Results[3].Error)
+	}
+}
+
+func TestCheckLinksV2Handler_InvalidJSON(t *testing.T) {
+	handler := NewCheckLinksV2Handler(&mockLinkService{})
+
+	req := httptest.NewRequest("POST", "/api/v2/check-links", bytes.NewReader([]byte("{")))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
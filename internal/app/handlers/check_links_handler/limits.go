@@ -0,0 +1,108 @@
+package check_links_handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+)
+
+type Limits struct {
+	MaxBodyBytes int64
+	MaxLinks     int
+	MaxURLLength int
+}
+
+var DefaultLimits = Limits{
+	MaxBodyBytes: 1 << 20,
+	MaxLinks:     1000,
+	MaxURLLength: 2048,
+}
+
+// LimitSet holds the limits applied to callers without an override and the
+// overrides of individual tenants. Other handlers accepting links share it.
+type LimitSet struct {
+	defaults Limits
+	tenants  map[string]Limits
+}
+
+type Option func(*LimitSet)
+
+func WithLimits(limits Limits) Option {
+	return func(s *LimitSet) {
+		s.defaults = s.defaults.override(limits)
+	}
+}
+
+// WithTenantLimits overrides the non-zero limits for one tenant.
+func WithTenantLimits(tenant string, limits Limits) Option {
+	return func(s *LimitSet) {
+		if s.tenants == nil {
+			s.tenants = make(map[string]Limits)
+		}
+		s.tenants[tenant] = limits
+	}
+}
+
+func NewLimitSet(opts ...Option) LimitSet {
+	limits := LimitSet{defaults: DefaultLimits}
+	for _, opt := range opts {
+		opt(&limits)
+	}
+	return limits
+}
+
+func (s LimitSet) ForTenant(tenant string) Limits {
+	return s.defaults.override(s.tenants[tenant])
+}
+
+func (l Limits) override(limits Limits) Limits {
+	if limits.MaxBodyBytes > 0 {
+		l.MaxBodyBytes = limits.MaxBodyBytes
+	}
+	if limits.MaxLinks > 0 {
+		l.MaxLinks = limits.MaxLinks
+	}
+	if limits.MaxURLLength > 0 {
+		l.MaxURLLength = limits.MaxURLLength
+	}
+	return l
+}
+
+func (l Limits) check(req CheckLinksRequest) *problem.Problem {
+	if len(req.Links) == 0 {
+		// Matches the schema validation, which rejects the same request with 400.
+		return problem.Validation("At least one link is required", []problem.FieldError{{Field: "links", Message: "must not be empty"}})
+	}
+
+	return l.CheckLinks("links", req.Links)
+}
+
+// CheckLinks applies the link count and URL length limits to links taken
+// from the request field named field.
+func (l Limits) CheckLinks(field string, links []string) *problem.Problem {
+	if len(links) > l.MaxLinks {
+		p := problem.New(http.StatusUnprocessableEntity, problem.TypeLimitExceeded,
+			fmt.Sprintf("A request may contain at most %d links, got %d", l.MaxLinks, len(links)))
+		p.Errors = []problem.FieldError{{Field: field, Message: fmt.Sprintf("must contain at most %d items", l.MaxLinks)}}
+		return p
+	}
+
+	var errs []problem.FieldError
+	for i, link := range links {
+		if len(link) > l.MaxURLLength {
+			errs = append(errs, problem.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Message: fmt.Sprintf("must be at most %d characters", l.MaxURLLength),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		p := problem.New(http.StatusUnprocessableEntity, problem.TypeLimitExceeded,
+			fmt.Sprintf("Links must not be longer than %d characters", l.MaxURLLength))
+		p.Errors = errs
+		return p
+	}
+
+	return nil
+}
@@ -1,5 +1,82 @@
 package check_links_handler
 
+import (
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
 type CheckLinksRequest struct {
-	Links []string `json:"links"`
-}
\ No newline at end of file
+	Links           []string               `json:"links"`
+	ValidateAnchors bool                   `json:"validate_anchors"`
+	DetectSoft404   bool                   `json:"detect_soft_404"`
+	SecurityAudit   bool                   `json:"security_audit"`
+	Assertions      map[string][]Assertion `json:"assertions,omitempty"`
+	Auth            map[string]Auth        `json:"auth,omitempty"`
+	HostAuth        []HostAuth             `json:"host_auth,omitempty"`
+}
+
+type Auth struct {
+	Credential string            `json:"credential,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+type HostAuth struct {
+	Hosts []string `json:"hosts"`
+	Auth
+}
+
+type Assertion struct {
+	Type              string `json:"type"`
+	Codes             []int  `json:"codes,omitempty"`
+	Header            string `json:"header,omitempty"`
+	Value             string `json:"value,omitempty"`
+	Path              string `json:"path,omitempty"`
+	Equals            any    `json:"equals,omitempty"`
+	MaxResponseTimeMs int    `json:"max_response_time_ms,omitempty"`
+}
+
+func (r CheckLinksRequest) assertions() map[string][]model.Assertion {
+	if len(r.Assertions) == 0 {
+		return nil
+	}
+
+	assertions := make(map[string][]model.Assertion, len(r.Assertions))
+	for link, list := range r.Assertions {
+		for _, a := range list {
+			assertions[link] = append(assertions[link], model.Assertion{
+				Type:            model.AssertionType(a.Type),
+				StatusCodes:     a.Codes,
+				Header:          a.Header,
+				Value:           a.Value,
+				JSONPath:        a.Path,
+				JSONValue:       a.Equals,
+				MaxResponseTime: time.Duration(a.MaxResponseTimeMs) * time.Millisecond,
+			})
+		}
+	}
+	return assertions
+}
+
+func (r CheckLinksRequest) auth() map[string]model.Auth {
+	if len(r.Auth) == 0 {
+		return nil
+	}
+
+	auth := make(map[string]model.Auth, len(r.Auth))
+	for link, a := range r.Auth {
+		auth[link] = model.Auth{Credential: a.Credential, Headers: a.Headers}
+	}
+	return auth
+}
+
+func (r CheckLinksRequest) hostAuth() []model.HostAuth {
+	var rules []model.HostAuth
+	for _, rule := range r.HostAuth {
+		rules = append(rules, model.HostAuth{
+			Hosts: rule.Hosts,
+			Auth:  model.Auth{Credential: rule.Credential, Headers: rule.Headers},
+		})
+	}
+	return rules
+}
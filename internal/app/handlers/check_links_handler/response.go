@@ -1,6 +1,103 @@
 package check_links_handler
 
+import (
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
 type CheckLinksResponse struct {
-	Links    map[string]string `json:"links"`
-	LinksNum int               `json:"links_num"`
-}
\ No newline at end of file
+	Links             map[string]string      `json:"links"`
+	LinksNum          int                    `json:"links_num"`
+	Errors            map[string]string      `json:"errors,omitempty"`
+	AssertionFailures map[string][]string    `json:"assertion_failures,omitempty"`
+	Soft404           map[string]float64     `json:"soft_404,omitempty"`
+	Certificates      map[string]Certificate `json:"certificates,omitempty"`
+	Security          *SecurityReport        `json:"security,omitempty"`
+	Schemes           map[string]string      `json:"schemes,omitempty"`
+	InsecureOnly      []string               `json:"insecure_only,omitempty"`
+	Proxies           map[string]string      `json:"proxies,omitempty"`
+}
+
+type Certificate struct {
+	Chain            []CertificateEntry `json:"chain"`
+	DaysUntilExpiry  int                `json:"days_until_expiry"`
+	HostnameMismatch bool               `json:"hostname_mismatch"`
+	SelfSigned       bool               `json:"self_signed"`
+	TLSVersion       string             `json:"tls_version,omitempty"`
+	CipherSuite      string             `json:"cipher_suite,omitempty"`
+	VerifyError      string             `json:"verify_error,omitempty"`
+}
+
+type CertificateEntry struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	SANs     []string  `json:"sans"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+func newCertificate(info *model.TLSInfo) Certificate {
+	cert := Certificate{
+		Chain:            make([]CertificateEntry, len(info.Chain)),
+		DaysUntilExpiry:  info.DaysUntilExpiry,
+		HostnameMismatch: info.HostnameMismatch,
+		SelfSigned:       info.SelfSigned,
+		TLSVersion:       info.Version,
+		CipherSuite:      info.CipherSuite,
+		VerifyError:      info.VerifyError,
+	}
+	for i, entry := range info.Chain {
+		cert.Chain[i] = CertificateEntry{
+			Subject:  entry.Subject,
+			Issuer:   entry.Issuer,
+			SANs:     entry.SANs,
+			NotAfter: entry.NotAfter,
+		}
+	}
+	return cert
+}
+
+type SecurityReport struct {
+	Score                int                      `json:"score"`
+	Audited              int                      `json:"audited"`
+	MissingHeaders       map[string]int           `json:"missing_headers"`
+	WithoutHTTPSRedirect int                      `json:"without_https_redirect"`
+	WithMixedContent     int                      `json:"with_mixed_content"`
+	Links                map[string]SecurityAudit `json:"links"`
+}
+
+type SecurityAudit struct {
+	Score            int               `json:"score"`
+	Headers          map[string]string `json:"headers"`
+	MissingHeaders   []string          `json:"missing_headers"`
+	RedirectsToHTTPS bool              `json:"redirects_to_https"`
+	MixedContent     []string          `json:"mixed_content,omitempty"`
+}
+
+func newSecurityReport(batch *model.LinkBatch) *SecurityReport {
+	if batch.Security == nil {
+		return nil
+	}
+
+	report := &SecurityReport{
+		Score:                batch.Security.Score,
+		Audited:              batch.Security.Audited,
+		MissingHeaders:       batch.Security.MissingHeaders,
+		WithoutHTTPSRedirect: batch.Security.WithoutHTTPSRedirect,
+		WithMixedContent:     batch.Security.WithMixedContent,
+		Links:                make(map[string]SecurityAudit),
+	}
+	for _, link := range batch.Links {
+		if link.Security == nil {
+			continue
+		}
+		report.Links[link.URL] = SecurityAudit{
+			Score:            link.Security.Score,
+			Headers:          link.Security.Headers,
+			MissingHeaders:   link.Security.MissingHeaders,
+			RedirectsToHTTPS: link.Security.RedirectsToHTTPS,
+			MixedContent:     link.Security.MixedContent,
+		}
+	}
+	return report
+}
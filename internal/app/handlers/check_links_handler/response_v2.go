@@ -0,0 +1,96 @@
+package check_links_handler
+
+import (
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type CheckLinksV2Response struct {
+	BatchID   string          `json:"batch_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Summary   Summary         `json:"summary"`
+	Results   []LinkResult    `json:"results"`
+	Security  *SecurityReport `json:"security,omitempty"`
+}
+
+type Summary struct {
+	Total    int            `json:"total"`
+	Unique   int            `json:"unique"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+type LinkResult struct {
+	Index             int            `json:"index"`
+	URL               string         `json:"url"`
+	NormalizedURL     string         `json:"normalized_url,omitempty"`
+	Status            string         `json:"status"`
+	StatusCode        int            `json:"status_code,omitempty"`
+	Error             string         `json:"error,omitempty"`
+	Scheme            string         `json:"scheme,omitempty"`
+	InsecureOnly      bool           `json:"insecure_only,omitempty"`
+	Proxy             string         `json:"proxy,omitempty"`
+	ResponseTimeMs    int64          `json:"response_time_ms"`
+	CheckedAt         time.Time      `json:"checked_at"`
+	FailedAssertions  []string       `json:"failed_assertions,omitempty"`
+	Soft404Confidence float64        `json:"soft_404_confidence,omitempty"`
+	Certificate       *Certificate   `json:"certificate,omitempty"`
+	Security          *SecurityAudit `json:"security,omitempty"`
+}
+
+func newCheckLinksV2Response(batch *model.LinkBatch) CheckLinksV2Response {
+	resp := CheckLinksV2Response{
+		BatchID:   batch.ID,
+		CreatedAt: batch.CreatedAt,
+		Summary: Summary{
+			Total:    len(batch.Links),
+			ByStatus: make(map[string]int),
+		},
+		Results:  make([]LinkResult, len(batch.Links)),
+		Security: newSecurityReport(batch),
+	}
+
+	unique := make(map[string]bool)
+	for i, link := range batch.Links {
+		resp.Summary.ByStatus[string(link.Status)]++
+
+		key := link.NormalizedURL
+		if key == "" {
+			key = link.URL
+		}
+		unique[key] = true
+
+		result := LinkResult{
+			Index:             i,
+			URL:               link.URL,
+			NormalizedURL:     link.NormalizedURL,
+			Status:            string(link.Status),
+			StatusCode:        link.StatusCode,
+			Error:             link.Error,
+			Scheme:            link.Scheme,
+			InsecureOnly:      link.InsecureOnly,
+			Proxy:             link.Proxy,
+			ResponseTimeMs:    link.ResponseTime.Milliseconds(),
+			CheckedAt:         link.CheckedAt,
+			FailedAssertions:  link.FailedAssertions,
+			Soft404Confidence: link.Soft404Confidence,
+		}
+		if link.TLS != nil {
+			cert := newCertificate(link.TLS)
+			result.Certificate = &cert
+		}
+		if link.Security != nil {
+			result.Security = &SecurityAudit{
+				Score:            link.Security.Score,
+				Headers:          link.Security.Headers,
+				MissingHeaders:   link.Security.MissingHeaders,
+				RedirectsToHTTPS: link.Security.RedirectsToHTTPS,
+				MixedContent:     link.Security.MixedContent,
+			}
+		}
+		resp.Results[i] = result
+	}
+	resp.Summary.Unique = len(unique)
+
+	return resp
+}
@@ -0,0 +1,72 @@
+package check_sitemap_handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type LinkService interface {
+	CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error)
+}
+
+type CheckSitemapHandler struct {
+	linkService LinkService
+}
+
+func NewCheckSitemapHandler(linkService service.LinkService) *CheckSitemapHandler {
+	return &CheckSitemapHandler{
+		linkService: linkService,
+	}
+}
+
+func (h *CheckSitemapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req CheckSitemapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Invalid JSON in check-sitemap request: %v", err)
+		problem.Write(w, r, problem.InvalidJSON(err))
+		return
+	}
+
+	log.Printf("Checking sitemap for %s", req.URL)
+	result, err := h.linkService.CheckSitemap(r.Context(), req.URL, 0)
+	if err != nil {
+		log.Printf("Error checking sitemap for %s: %v", req.URL, err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("Created batch %s with %d links from %d sitemaps", result.Batch.ID, len(result.Batch.Links), len(result.Sitemaps))
+
+	resp := CheckSitemapResponse{
+		BatchID:    result.Batch.ID,
+		Sitemaps:   result.Sitemaps,
+		Links:      toLinkResults(result.Batch.Links),
+		Disallowed: result.Disallowed,
+		NonOK:      toLinkResults(result.NonOK),
+		Truncated:  result.Truncated,
+	}
+	if resp.Disallowed == nil {
+		resp.Disallowed = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toLinkResults(links []model.LinkCheck) []LinkResult {
+	results := make([]LinkResult, len(links))
+	for i, link := range links {
+		results[i] = LinkResult{
+			URL:        link.URL,
+			Status:     string(link.Status),
+			StatusCode: link.StatusCode,
+		}
+	}
+	return results
+}
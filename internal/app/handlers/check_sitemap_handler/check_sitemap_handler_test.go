@@ -0,0 +1,89 @@
+package check_sitemap_handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type mockLinkService struct{}
+
+func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	if target == "https://nositemap.example.com" {
+		return nil, fmt.Errorf("%w: no sitemap", service.ErrSitemapNotFound)
+	}
+	gone := model.LinkCheck{URL: "https://example.com/gone", Status: model.StatusNotAvailable, StatusCode: 404}
+	return &model.SitemapResult{
+		Batch: &model.LinkBatch{
+			ID: "b4",
+			Links: []model.LinkCheck{
+				{URL: "https://example.com/", Status: model.StatusAvailable, StatusCode: 200},
+				gone,
+			},
+		},
+		Sitemaps: []string{"https://example.com/sitemap.xml"},
+		NonOK:    []model.LinkCheck{gone},
+	}, nil
+}
+
+func (m *mockLinkService) GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func (m *mockLinkService) GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func TestCheckSitemapHandler_ServeHTTP(t *testing.T) {
+	handler := NewCheckSitemapHandler(&mockLinkService{})
+
+	req := httptest.NewRequest("POST", "/api/check-sitemap", bytes.NewReader([]byte(`{"url":"https://example.com"}`)))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var resp CheckSitemapResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.BatchID != "b4" || len(resp.Links) != 2 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	if len(resp.NonOK) != 1 || resp.NonOK[0].StatusCode != 404 {
+		t.Errorf("Expected one non-200 entry, got %+v", resp.NonOK)
+	}
+}
+
+func TestCheckSitemapHandler_SitemapNotFound(t *testing.T) {
+	handler := NewCheckSitemapHandler(&mockLinkService{})
+
+	req := httptest.NewRequest("POST", "/api/check-sitemap", bytes.NewReader([]byte(`{"url":"https://nositemap.example.com"}`)))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", w.Code)
+	}
+}
@@ -0,0 +1,5 @@
+package check_sitemap_handler
+
+type CheckSitemapRequest struct {
+	URL string `json:"url"`
+}
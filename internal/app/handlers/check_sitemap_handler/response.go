@@ -0,0 +1,16 @@
+package check_sitemap_handler
+
+type CheckSitemapResponse struct {
+	BatchID    string       `json:"batch_id"`
+	Sitemaps   []string     `json:"sitemaps"`
+	Links      []LinkResult `json:"links"`
+	Disallowed []string     `json:"disallowed"`
+	NonOK      []LinkResult `json:"non_ok"`
+	Truncated  bool         `json:"truncated,omitempty"`
+}
+
+type LinkResult struct {
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+}
@@ -0,0 +1,63 @@
+package crawl_handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type LinkService interface {
+	Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error)
+}
+
+type CrawlHandler struct {
+	linkService LinkService
+}
+
+func NewCrawlHandler(linkService service.LinkService) *CrawlHandler {
+	return &CrawlHandler{
+		linkService: linkService,
+	}
+}
+
+func (h *CrawlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req CrawlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Invalid JSON in crawl request: %v", err)
+		problem.Write(w, r, problem.InvalidJSON(err))
+		return
+	}
+
+	log.Printf("Crawling %s", req.URL)
+	result, err := h.linkService.Crawl(r.Context(), req.options())
+	if err != nil {
+		log.Printf("Error crawling %s: %v", req.URL, err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	log.Printf("Created batch %s with %d links from %d pages", result.Batch.ID, len(result.Batch.Links), len(result.PagesCrawled))
+
+	resp := CrawlResponse{
+		BatchID:      result.Batch.ID,
+		PagesCrawled: result.PagesCrawled,
+		Truncated:    result.Truncated,
+		Links:        make([]LinkResult, len(result.Batch.Links)),
+	}
+
+	for i, link := range result.Batch.Links {
+		resp.Links[i] = LinkResult{
+			URL:     link.URL,
+			Status:  string(link.Status),
+			Sources: link.Sources,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
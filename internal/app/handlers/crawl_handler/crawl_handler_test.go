@@ -0,0 +1,96 @@
+package crawl_handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type mockLinkService struct {
+	opts model.CrawlOptions
+}
+
+func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	m.opts = opts
+	if opts.StartURL == "" {
+		return nil, fmt.Errorf("%w: empty start URL", service.ErrInvalidURL)
+	}
+	return &model.CrawlResult{
+		Batch: &model.LinkBatch{
+			ID: "b3",
+			Links: []model.LinkCheck{
+				{URL: "https://example.com/", Status: model.StatusAvailable},
+				{URL: "https://example.com/missing", Status: model.StatusNotAvailable, Sources: []string{"https://example.com/"}},
+			},
+		},
+		PagesCrawled: []string{"https://example.com/"},
+	}, nil
+}
+
+func (m *mockLinkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func (m *mockLinkService) GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error) {
+	return []byte("fake pdf"), nil
+}
+
+func TestCrawlHandler_ServeHTTP(t *testing.T) {
+	mock := &mockLinkService{}
+	handler := NewCrawlHandler(mock)
+
+	body, _ := json.Marshal(map[string]interface{}{"url": "https://example.com/", "max_pages": 10})
+	req := httptest.NewRequest("POST", "/api/crawl", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	if !mock.opts.SameHost || mock.opts.MaxDepth != defaultMaxDepth || mock.opts.MaxPages != 10 {
+		t.Errorf("Unexpected crawl options: %+v", mock.opts)
+	}
+
+	var resp CrawlResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.BatchID != "b3" {
+		t.Errorf("Expected batch ID b3, got %s", resp.BatchID)
+	}
+
+	if len(resp.Links) != 2 || resp.Links[1].Sources[0] != "https://example.com/" {
+		t.Errorf("Expected broken link with its source page, got %+v", resp.Links)
+	}
+}
+
+func TestCrawlHandler_InvalidURL(t *testing.T) {
+	handler := NewCrawlHandler(&mockLinkService{})
+
+	req := httptest.NewRequest("POST", "/api/crawl", bytes.NewReader([]byte(`{"url":""}`)))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,30 @@
+package crawl_handler
+
+import "github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+
+const defaultMaxDepth = 2
+
+type CrawlRequest struct {
+	URL        string `json:"url"`
+	SameHost   *bool  `json:"same_host"`
+	PathPrefix string `json:"path_prefix"`
+	MaxDepth   *int   `json:"max_depth"`
+	MaxPages   int    `json:"max_pages"`
+}
+
+func (r CrawlRequest) options() model.CrawlOptions {
+	opts := model.CrawlOptions{
+		StartURL:   r.URL,
+		SameHost:   true,
+		PathPrefix: r.PathPrefix,
+		MaxDepth:   defaultMaxDepth,
+		MaxPages:   r.MaxPages,
+	}
+	if r.SameHost != nil {
+		opts.SameHost = *r.SameHost
+	}
+	if r.MaxDepth != nil {
+		opts.MaxDepth = *r.MaxDepth
+	}
+	return opts
+}
@@ -0,0 +1,14 @@
+package crawl_handler
+
+type CrawlResponse struct {
+	BatchID      string       `json:"batch_id"`
+	PagesCrawled []string     `json:"pages_crawled"`
+	Truncated    bool         `json:"truncated,omitempty"`
+	Links        []LinkResult `json:"links"`
+}
+
+type LinkResult struct {
+	URL     string   `json:"url"`
+	Status  string   `json:"status"`
+	Sources []string `json:"sources,omitempty"`
+}
@@ -1,15 +1,18 @@
 package generate_report_handler
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
 )
 
 type LinkService interface {
-	GenerateReport(batchIDs []int) ([]byte, error)
+	GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error)
+	GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error)
 }
 
 type GenerateReportHandler struct {
@@ -26,15 +29,19 @@ func (h *GenerateReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	var req GenerateReportRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("Invalid JSON in generate-report request: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		problem.Write(w, r, problem.InvalidJSON(err))
 		return
 	}
 
 	log.Printf("Generating report for batches: %v", req.LinksList)
-	pdfData, err := h.linkService.GenerateReport(req.LinksList)
+	pdfData, err := h.linkService.GenerateReportByNumbers(r.Context(), req.LinksList)
+	writeReport(w, r, pdfData, err)
+}
+
+func writeReport(w http.ResponseWriter, r *http.Request, pdfData []byte, err error) {
 	if err != nil {
 		log.Printf("Error generating report: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		problem.WriteError(w, r, err)
 		return
 	}
 
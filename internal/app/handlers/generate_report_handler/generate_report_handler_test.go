@@ -4,25 +4,53 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
 )
 
-type mockLinkService struct{}
+type mockLinkService struct {
+	err     error
+	ids     []string
+	numbers []int
+}
+
+func (m *mockLinkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error) {
+	m.ids = batchIDs
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []byte("fake pdf data"), nil
+}
 
-func (m *mockLinkService) GenerateReport(batchIDs []int) ([]byte, error) {
+func (m *mockLinkService) GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error) {
+	m.numbers = numbers
+	if m.err != nil {
+		return nil, m.err
+	}
 	return []byte("fake pdf data"), nil
 }
 
-func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string) (*model.LinkBatch, error) {
+func (m *mockLinkService) CheckLinks(ctx context.Context, urls []string, opts ...service.CheckOption) (*model.LinkBatch, error) {
 	return nil, nil
 }
 
 func TestGenerateReportHandler_ServeHTTP(t *testing.T) {
-	handler := NewGenerateReportHandler(&mockLinkService{})
+	mock := &mockLinkService{}
+	handler := NewGenerateReportHandler(mock)
 
 	reqBody := GenerateReportRequest{
 		LinksList: []int{1, 2},
@@ -47,4 +75,47 @@ func TestGenerateReportHandler_ServeHTTP(t *testing.T) {
 	if w.Body.Len() == 0 {
 		t.Error("Expected PDF data, got empty response")
 	}
-}
\ No newline at end of file
+
+	if len(mock.numbers) != 2 || mock.numbers[0] != 1 || mock.numbers[1] != 2 {
+		t.Errorf("Expected batch numbers [1 2], got %v", mock.numbers)
+	}
+}
+
+func TestGenerateReportHandler_Problems(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		err         error
+		status      int
+		problemType string
+	}{
+		{"invalid json", "{", nil, http.StatusBadRequest, problem.TypeInvalidJSON},
+		{"service failure", `{"links_list":[1]}`, errors.New("boom"), http.StatusInternalServerError, problem.TypeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGenerateReportHandler(&mockLinkService{err: tt.err})
+
+			req := httptest.NewRequest("POST", "/api/generate-report", bytes.NewReader([]byte(tt.body)))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != problem.ContentType {
+				t.Errorf("Expected %s, got %s", problem.ContentType, contentType)
+			}
+
+			var p problem.Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+			if p.Type != tt.problemType || p.Status != tt.status || p.Instance != "/api/generate-report" {
+				t.Errorf("Unexpected problem: %+v", p)
+			}
+		})
+	}
+}
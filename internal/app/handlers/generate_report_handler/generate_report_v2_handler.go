@@ -0,0 +1,35 @@
+package generate_report_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+)
+
+// GenerateReportV2Handler takes the opaque batch IDs returned by the v2 and
+// newer endpoints.
+type GenerateReportV2Handler struct {
+	linkService LinkService
+}
+
+func NewGenerateReportV2Handler(linkService service.LinkService) *GenerateReportV2Handler {
+	return &GenerateReportV2Handler{
+		linkService: linkService,
+	}
+}
+
+func (h *GenerateReportV2Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req GenerateReportV2Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Invalid JSON in generate-report request: %v", err)
+		problem.Write(w, r, problem.InvalidJSON(err))
+		return
+	}
+
+	log.Printf("Generating report for batches: %v", req.LinksList)
+	pdfData, err := h.linkService.GenerateReport(r.Context(), req.LinksList)
+	writeReport(w, r, pdfData, err)
+}
@@ -0,0 +1,25 @@
+package generate_report_handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenerateReportV2Handler_ServeHTTP(t *testing.T) {
+	mock := &mockLinkService{}
+	handler := NewGenerateReportV2Handler(mock)
+
+	req := httptest.NewRequest("POST", "/api/v2/generate-report", strings.NewReader(`{"links_list":["b1","b2"]}`))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("Expected PDF with status 200, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if len(mock.ids) != 2 || mock.ids[0] != "b1" || mock.ids[1] != "b2" {
+		t.Errorf("Expected batch IDs [b1 b2], got %v", mock.ids)
+	}
+}
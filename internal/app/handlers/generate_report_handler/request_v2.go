@@ -0,0 +1,5 @@
+package generate_report_handler
+
+type GenerateReportV2Request struct {
+	LinksList []string `json:"links_list"`
+}
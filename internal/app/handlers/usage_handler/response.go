@@ -0,0 +1,63 @@
+package usage_handler
+
+import (
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type UsageResponse struct {
+	Tenant SubjectUsage  `json:"tenant"`
+	Key    *SubjectUsage `json:"key,omitempty"`
+}
+
+type SubjectUsage struct {
+	Subject       string        `json:"subject"`
+	Quota         Quota         `json:"quota"`
+	ActiveBatches int           `json:"active_batches"`
+	StoredBatches *int          `json:"stored_batches,omitempty"`
+	History       []UsageBucket `json:"history"`
+}
+
+// Quota reports zero for unlimited quotas.
+type Quota struct {
+	ChecksPerHour     int `json:"checks_per_hour"`
+	LinksPerBatch     int `json:"links_per_batch"`
+	ConcurrentBatches int `json:"concurrent_batches"`
+	StoredBatches     int `json:"stored_batches,omitempty"`
+}
+
+type UsageBucket struct {
+	Start    time.Time `json:"start"`
+	Checks   int       `json:"checks"`
+	Batches  int       `json:"batches"`
+	Rejected int       `json:"rejected"`
+}
+
+func newUsageResponse(report *model.UsageReport) UsageResponse {
+	resp := UsageResponse{Tenant: newSubjectUsage(report.Tenant)}
+	resp.Tenant.StoredBatches = &report.Tenant.StoredBatches
+	if report.Key != nil {
+		key := newSubjectUsage(*report.Key)
+		resp.Key = &key
+	}
+	return resp
+}
+
+func newSubjectUsage(usage model.SubjectUsage) SubjectUsage {
+	resp := SubjectUsage{
+		Subject:       usage.Subject,
+		Quota:         Quota(usage.Quota),
+		ActiveBatches: usage.Active,
+		History:       make([]UsageBucket, len(usage.History)),
+	}
+	for i, bucket := range usage.History {
+		resp.History[i] = UsageBucket{
+			Start:    bucket.Start,
+			Checks:   bucket.Checks,
+			Batches:  bucket.Batches,
+			Rejected: bucket.Rejected,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,58 @@
+package usage_handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/quota/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/quota/service"
+)
+
+const defaultHours = 24
+
+var maxHours = int(repository.Retention / time.Hour)
+
+type UsageReader interface {
+	Usage(ctx context.Context, since time.Time) (*model.UsageReport, error)
+}
+
+type UsageHandler struct {
+	quotaService UsageReader
+}
+
+func NewUsageHandler(quotaService service.QuotaService) *UsageHandler {
+	return &UsageHandler{
+		quotaService: quotaService,
+	}
+}
+
+func (h *UsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hours := defaultHours
+	if raw := r.URL.Query().Get("hours"); raw != "" {
+		var err error
+		if hours, err = strconv.Atoi(raw); err != nil || hours < 1 || hours > maxHours {
+			problem.Write(w, r, problem.Validation("Invalid query parameter", []problem.FieldError{
+				{Field: "hours", Message: fmt.Sprintf("must be a number between 1 and %d", maxHours)},
+			}))
+			return
+		}
+	}
+
+	since := time.Now().Add(-time.Duration(hours-1) * time.Hour)
+	report, err := h.quotaService.Usage(r.Context(), since)
+	if err != nil {
+		log.Printf("Error reading usage: %v", err)
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newUsageResponse(report))
+}
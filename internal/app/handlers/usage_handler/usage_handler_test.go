@@ -0,0 +1,112 @@
+package usage_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/quota/service"
+)
+
+type mockQuotaService struct {
+	since time.Time
+	err   error
+}
+
+func (m *mockQuotaService) Begin(ctx context.Context, links int) (service.Finish, error) {
+	return nil, nil
+}
+
+func (m *mockQuotaService) BeginUpTo(ctx context.Context) (service.Finish, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockQuotaService) Configure(opts ...service.Option) {}
+
+func (m *mockQuotaService) Usage(ctx context.Context, since time.Time) (*model.UsageReport, error) {
+	m.since = since
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &model.UsageReport{
+		Tenant: model.SubjectUsage{
+			Subject:       "tenant:team-a",
+			Quota:         model.Quota{ChecksPerHour: 100, StoredBatches: 10},
+			Active:        1,
+			StoredBatches: 4,
+			History:       []model.UsageBucket{{Start: since, Usage: model.Usage{Checks: 12, Batches: 2}}},
+		},
+		Key: &model.SubjectUsage{Subject: "key:k1", Quota: model.Quota{LinksPerBatch: 50}},
+	}, nil
+}
+
+func TestUsageHandler(t *testing.T) {
+	mock := &mockQuotaService{}
+	handler := NewUsageHandler(mock)
+
+	req := httptest.NewRequest("GET", "/api/usage?hours=6", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if elapsed := time.Since(mock.since); elapsed < 5*time.Hour || elapsed > 6*time.Hour {
+		t.Errorf("Expected usage of the last 6 hours, got since %v", mock.since)
+	}
+
+	var resp UsageResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Tenant.Subject != "tenant:team-a" || resp.Tenant.Quota.ChecksPerHour != 100 || resp.Tenant.ActiveBatches != 1 {
+		t.Errorf("Unexpected tenant usage: %+v", resp.Tenant)
+	}
+	if resp.Tenant.StoredBatches == nil || *resp.Tenant.StoredBatches != 4 {
+		t.Errorf("Expected stored batches of the tenant, got %v", resp.Tenant.StoredBatches)
+	}
+	if len(resp.Tenant.History) != 1 || resp.Tenant.History[0].Checks != 12 {
+		t.Errorf("Unexpected history: %+v", resp.Tenant.History)
+	}
+	if resp.Key == nil || resp.Key.Quota.LinksPerBatch != 50 || resp.Key.StoredBatches != nil {
+		t.Errorf("Unexpected key usage: %+v", resp.Key)
+	}
+}
+
+func TestUsageHandler_Problems(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{"not a number", "?hours=day", nil, http.StatusBadRequest},
+		{"too long", "?hours=1000", nil, http.StatusBadRequest},
+		{"service failure", "", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewUsageHandler(&mockQuotaService{err: tt.err})
+
+			req := httptest.NewRequest("GET", "/api/usage"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != problem.ContentType {
+				t.Errorf("Expected %s, got %s", problem.ContentType, contentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+func (s *Spec) Validate(schema *Schema, value any) []problem.FieldError {
+	var errs []problem.FieldError
+	s.validate(schema, value, "", &errs)
+	return errs
+}
+
+func (s *Spec) validate(schema *Schema, value any, field string, errs *[]problem.FieldError) {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, problem.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if schema.Type != "" {
+			fail("must be of type %s", schema.Type)
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("must be of type object")
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				*errs = append(*errs, problem.FieldError{Field: join(field, name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok {
+				s.validate(property, object[name], join(field, name), errs)
+			} else if schema.AdditionalProperties != nil {
+				s.validate(schema.AdditionalProperties, object[name], join(field, name), errs)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			fail("must be of type array")
+			return
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			fail("must contain at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			fail("must contain at most %d items", *schema.MaxItems)
+		}
+		for i, item := range array {
+			s.validate(schema.Items, item, field+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be of type string")
+			return
+		}
+		if schema.MinLength != nil && len(str) < *schema.MinLength {
+			fail("must be at least %d characters long", *schema.MinLength)
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok || (schema.Type == "integer" && number != math.Trunc(number)) {
+			fail("must be of type %s", schema.Type)
+			return
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			fail("must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			fail("must be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be of type boolean")
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if reflect.DeepEqual(allowed, value) {
+				return
+			}
+		}
+		fail("must be one of %v", schema.Enum)
+	}
+}
+
+func join(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+)
+
+type ValidationMiddleware struct {
+	h            http.Handler
+	spec         *Spec
+	maxBodyBytes int64
+}
+
+func NewValidationMiddleware(h http.Handler, spec *Spec, maxBodyBytes int64) http.Handler {
+	return &ValidationMiddleware{h: h, spec: spec, maxBodyBytes: maxBodyBytes}
+}
+
+func (m *ValidationMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	schema, required := m.requestSchema(r)
+	if schema == nil {
+		m.h.ServeHTTP(w, r)
+		return
+	}
+
+	reader := r.Body
+	if m.maxBodyBytes > 0 {
+		reader = http.MaxBytesReader(w, r.Body, m.maxBodyBytes)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		log.Printf("Failed to read request body: %v", err)
+		problem.Write(w, r, problem.FromDecodeError(err))
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			problem.Write(w, r, problem.Validation("Request body is required", nil))
+			return
+		}
+		m.h.ServeHTTP(w, r)
+		return
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		problem.Write(w, r, problem.InvalidJSON(err))
+		return
+	}
+
+	if errs := m.spec.Validate(schema, value); len(errs) > 0 {
+		log.Printf("Request to %s does not match schema: %d errors", r.URL.Path, len(errs))
+		problem.Write(w, r, problem.Validation("Request does not match the API schema", errs))
+		return
+	}
+
+	m.h.ServeHTTP(w, r)
+}
+
+func (m *ValidationMiddleware) requestSchema(r *http.Request) (*Schema, bool) {
+	op := m.spec.operation(r.Method, r.URL.Path)
+	if op == nil || op.RequestBody == nil {
+		return nil, false
+	}
+
+	content, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return nil, false
+	}
+
+	return content.Schema, op.RequestBody.Required
+}
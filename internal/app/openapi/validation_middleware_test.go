@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/app/problem"
+)
+
+func TestValidationMiddleware(t *testing.T) {
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	var received string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	})
+	handler := NewValidationMiddleware(next, spec, 1<<20)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		code   int
+		fields []string
+	}{
+		{"valid request", "/api/check-links", `{"links": ["example.com"]}`, http.StatusOK, nil},
+		{"missing required field", "/api/check-links", `{"validate_anchors": true}`, http.StatusBadRequest, []string{"links"}},
+		{"wrong item type", "/api/check-links", `{"links": ["a.com", 1]}`, http.StatusBadRequest, []string{"links[1]"}},
+		{"too many items", "/api/generate-report", `{"links_list": [` + strings.Repeat(`1,`, 100) + `1]}`, http.StatusBadRequest, []string{"links_list"}},
+		{"v2 batch id in v1", "/api/generate-report", `{"links_list": ["b"]}`, http.StatusBadRequest, []string{"links_list[0]"}},
+		{"v1 batch number in v2", "/api/v2/generate-report", `{"links_list": [1]}`, http.StatusBadRequest, []string{"links_list[0]"}},
+		{"unknown assertion type", "/api/check-links", `{"links": ["a.com"], "assertions": {"a.com": [{"type": "nope"}]}}`, http.StatusBadRequest, []string{"assertions.a.com[0].type"}},
+		{"fractional integer", "/api/generate-report", `{"links_list": [1.5]}`, http.StatusBadRequest, []string{"links_list[0]"}},
+		{"empty body", "/api/crawl", ``, http.StatusBadRequest, []string{}},
+		{"invalid json", "/api/crawl", `{`, http.StatusBadRequest, []string{}},
+		{"unknown path", "/api/unknown", `{`, http.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Fatalf("Expected status %d, got %d", tt.code, w.Code)
+			}
+
+			if tt.code == http.StatusOK {
+				if received != tt.body {
+					t.Errorf("Expected body to be passed through, got %q", received)
+				}
+				return
+			}
+
+			var resp problem.Problem
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if len(resp.Errors) != len(tt.fields) {
+				t.Fatalf("Expected %d details, got %+v", len(tt.fields), resp.Errors)
+			}
+			for i, field := range tt.fields {
+				if resp.Errors[i].Field != field {
+					t.Errorf("Expected field %q, got %q", field, resp.Errors[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationMiddleware_SkipsMultipart(t *testing.T) {
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	called := false
+	handler := NewValidationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), spec, 1<<20)
+
+	req := httptest.NewRequest("POST", "/api/check-documents", strings.NewReader("--x--"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Error("Expected multipart request to reach the handler")
+	}
+}
+
+func TestValidationMiddleware_BodyTooLarge(t *testing.T) {
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	handler := NewValidationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected oversized request not to reach the handler")
+	}), spec, 16)
+
+	req := httptest.NewRequest("POST", "/api/check-links", strings.NewReader(`{"links": ["https://example.com/very/long"]}`))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, got %d", w.Code)
+	}
+}
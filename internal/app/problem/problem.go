@@ -0,0 +1,157 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	keyservice "github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	quotaservice "github.com/eightjhonydolly/05.12.2025/internal/domain/quota/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/http/middlewares"
+)
+
+const ContentType = "application/problem+json"
+
+const (
+	TypeInvalidJSON      = "/problems/invalid-json"
+	TypeInvalidBody      = "/problems/invalid-body"
+	TypeBodyTooLarge     = "/problems/body-too-large"
+	TypeLimitExceeded    = "/problems/limit-exceeded"
+	TypeValidation       = "/problems/validation-error"
+	TypeInvalidURL       = "/problems/invalid-url"
+	TypeInvalidAssertion = "/problems/invalid-assertion"
+	TypeInvalidAuth      = "/problems/invalid-auth"
+	TypeSitemapNotFound  = "/problems/sitemap-not-found"
+	TypeUnauthorized     = "/problems/unauthorized"
+	TypeForbidden        = "/problems/forbidden"
+	TypeInvalidScope     = "/problems/invalid-scope"
+	TypeKeyNotFound      = "/problems/key-not-found"
+	TypeStaticKey        = "/problems/static-key"
+	TypeInvalidQuota     = "/problems/invalid-quota"
+	TypeQuotaExceeded    = "/problems/quota-exceeded"
+	TypeRateLimited      = "/problems/rate-limited"
+	TypeTimeout          = "/problems/timeout"
+	TypeInternal         = "/problems/internal-error"
+)
+
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+
+	// RetryAfter is sent as the Retry-After header when set.
+	RetryAfter time.Duration `json:"-"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func New(status int, problemType, detail string) *Problem {
+	return &Problem{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func InvalidJSON(err error) *Problem {
+	return New(http.StatusBadRequest, TypeInvalidJSON, err.Error())
+}
+
+func Validation(detail string, errs []FieldError) *Problem {
+	p := New(http.StatusBadRequest, TypeValidation, detail)
+	p.Errors = errs
+	return p
+}
+
+func FromDecodeError(err error) *Problem {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return New(http.StatusRequestEntityTooLarge, TypeBodyTooLarge,
+			fmt.Sprintf("Request body must not exceed %d bytes", maxBytesErr.Limit))
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return Validation("Request contains unknown fields", []FieldError{
+			{Field: strings.Trim(field, `"`), Message: "is not a known field"},
+		})
+	}
+
+	return InvalidJSON(err)
+}
+
+func FromError(err error) *Problem {
+	var exceeded *quotaservice.ExceededError
+	var limited *middlewares.RateLimitedError
+	switch {
+	case errors.As(err, &exceeded):
+		p := New(http.StatusTooManyRequests, TypeQuotaExceeded, err.Error())
+		p.RetryAfter = exceeded.RetryAfter
+		return p
+	case errors.As(err, &limited):
+		p := New(http.StatusTooManyRequests, TypeRateLimited, "Too many requests, slow down")
+		p.RetryAfter = limited.RetryAfter
+		return p
+	case errors.Is(err, service.ErrInvalidURL):
+		return New(http.StatusBadRequest, TypeInvalidURL, err.Error())
+	case errors.Is(err, service.ErrInvalidAssertion):
+		return New(http.StatusBadRequest, TypeInvalidAssertion, err.Error())
+	case errors.Is(err, service.ErrInvalidAuth):
+		return New(http.StatusBadRequest, TypeInvalidAuth, err.Error())
+	case errors.Is(err, middlewares.ErrUnauthenticated):
+		return New(http.StatusUnauthorized, TypeUnauthorized, err.Error())
+	case errors.Is(err, middlewares.ErrForbidden):
+		return New(http.StatusForbidden, TypeForbidden, err.Error())
+	case errors.Is(err, keyservice.ErrInvalidScope):
+		return New(http.StatusBadRequest, TypeInvalidScope, err.Error())
+	case errors.Is(err, keyservice.ErrInvalidQuota):
+		return New(http.StatusBadRequest, TypeInvalidQuota, err.Error())
+	case errors.Is(err, keyservice.ErrKeyNotFound):
+		return New(http.StatusNotFound, TypeKeyNotFound, err.Error())
+	case errors.Is(err, keyservice.ErrStaticKey):
+		return New(http.StatusConflict, TypeStaticKey, "API key is defined in configuration and cannot be revoked through the API")
+	case errors.Is(err, service.ErrSitemapNotFound):
+		return New(http.StatusUnprocessableEntity, TypeSitemapNotFound, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(http.StatusGatewayTimeout, TypeTimeout, "The request took too long to complete")
+	default:
+		return New(http.StatusInternalServerError, TypeInternal, "An unexpected error occurred")
+	}
+}
+
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	p.Instance = r.URL.Path
+	p.RequestID = middlewares.RequestIDFromContext(r.Context())
+
+	w.Header().Set("Content-Type", ContentType)
+	if p.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(p.RetryAfter.Seconds()))))
+	}
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("Failed to write problem response: %v", err)
+	}
+}
+
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	p := FromError(err)
+	if p.Status >= http.StatusInternalServerError {
+		log.Printf("Request %s %s failed: %v", r.URL.Path, middlewares.RequestIDFromContext(r.Context()), err)
+	}
+	Write(w, r, p)
+}
@@ -0,0 +1,99 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	keyservice "github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	quotaservice "github.com/eightjhonydolly/05.12.2025/internal/domain/quota/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/http/middlewares"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		err         error
+		status      int
+		problemType string
+	}{
+		{fmt.Errorf("%w: bad", service.ErrInvalidURL), http.StatusBadRequest, TypeInvalidURL},
+		{fmt.Errorf("%w: bad", service.ErrInvalidAssertion), http.StatusBadRequest, TypeInvalidAssertion},
+		{fmt.Errorf("%w: bad", service.ErrInvalidAuth), http.StatusBadRequest, TypeInvalidAuth},
+		{middlewares.ErrUnauthenticated, http.StatusUnauthorized, TypeUnauthorized},
+		{fmt.Errorf("%w: admin required", middlewares.ErrForbidden), http.StatusForbidden, TypeForbidden},
+		{fmt.Errorf("%w: \"x\"", keyservice.ErrInvalidScope), http.StatusBadRequest, TypeInvalidScope},
+		{fmt.Errorf("%w: negative", keyservice.ErrInvalidQuota), http.StatusBadRequest, TypeInvalidQuota},
+		{keyservice.ErrKeyNotFound, http.StatusNotFound, TypeKeyNotFound},
+		{keyservice.ErrStaticKey, http.StatusConflict, TypeStaticKey},
+		{&quotaservice.ExceededError{Quota: "stored_batches"}, http.StatusTooManyRequests, TypeQuotaExceeded},
+		{&middlewares.RateLimitedError{RetryAfter: time.Second}, http.StatusTooManyRequests, TypeRateLimited},
+		{fmt.Errorf("%w: example.com", service.ErrSitemapNotFound), http.StatusUnprocessableEntity, TypeSitemapNotFound},
+		{fmt.Errorf("crawl: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, TypeTimeout},
+		{errors.New("disk on fire"), http.StatusInternalServerError, TypeInternal},
+	}
+
+	for _, tt := range tests {
+		p := FromError(tt.err)
+		if p.Status != tt.status || p.Type != tt.problemType {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.status, tt.problemType, p.Status, p.Type)
+		}
+	}
+
+	if p := FromError(errors.New("secret connection string")); p.Detail == "secret connection string" {
+		t.Error("Expected internal error details not to leak")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	handler := middlewares.NewRequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, Validation("bad input", []FieldError{{Field: "links", Message: "is required"}}))
+	}))
+
+	req := httptest.NewRequest("POST", "/api/check-links", nil)
+	req.Header.Set(middlewares.RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != ContentType {
+		t.Errorf("Expected %s, got %s", ContentType, contentType)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+
+	if p.Type != TypeValidation || p.Title != "Bad Request" || p.Status != 400 || p.Detail != "bad input" {
+		t.Errorf("Unexpected problem: %+v", p)
+	}
+	if p.Instance != "/api/check-links" || p.RequestID != "req-42" {
+		t.Errorf("Expected instance and request ID, got %q %q", p.Instance, p.RequestID)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "links" {
+		t.Errorf("Expected field error, got %+v", p.Errors)
+	}
+}
+
+func TestWriteError_RetryAfter(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/check-links", nil)
+	w := httptest.NewRecorder()
+
+	WriteError(w, req, &quotaservice.ExceededError{Quota: "checks_per_hour", Limit: 10, RetryAfter: 1500 * time.Millisecond})
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Errorf("Expected Retry-After to be rounded up to 2 seconds, got %q", retryAfter)
+	}
+}
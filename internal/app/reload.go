@@ -0,0 +1,142 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/config"
+)
+
+var restartOnlyKeys = []string{"server.", "storage.", "logging.format", "reload.", "rate_limit.idle_timeout"}
+
+type swappableHandler struct {
+	current atomic.Pointer[http.Handler]
+}
+
+func (s *swappableHandler) Store(h http.Handler) {
+	s.current.Store(&h)
+}
+
+func (s *swappableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*s.current.Load()).ServeHTTP(w, r)
+}
+
+func (app *App) currentConfig() *config.Config {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	return app.config
+}
+
+func (app *App) Reload() error {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	next, err := app.loader.Load()
+	if err != nil {
+		log.Printf("Config reload rejected, keeping current config: %v", err)
+		return err
+	}
+
+	for _, change := range config.Diff(app.config, next) {
+		if requiresRestart(change.Key) {
+			log.Printf("Config change %s requires a restart and was ignored", change)
+		}
+	}
+	next.Server = app.config.Server
+	next.Storage = app.config.Storage
+	next.Logging.Format = app.config.Logging.Format
+	next.Reload = app.config.Reload
+	next.RateLimit.IdleTimeout = app.config.RateLimit.IdleTimeout
+
+	changes := config.Diff(app.config, next)
+	if len(changes) == 0 {
+		log.Println("Config reloaded, no changes")
+		return nil
+	}
+
+	if err := app.services.configure(next); err != nil {
+		log.Printf("Config reload rejected, keeping current config: %v", err)
+		return err
+	}
+	handler, err := buildHandler(next, app.services)
+	if err != nil {
+		log.Printf("Config reload rejected, keeping current config: %v", err)
+		return fmt.Errorf("buildHandler: %w", err)
+	}
+
+	app.handler.Store(handler)
+	app.setLogLevel(next.Logging.Level)
+	app.config = next
+
+	for _, change := range changes {
+		log.Printf("Config changed %s", change)
+	}
+	return nil
+}
+
+func requiresRestart(key string) bool {
+	for _, prefix := range restartOnlyKeys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (app *App) setLogLevel(value string) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		level = slog.LevelInfo
+	}
+	app.logLevel.Set(level)
+}
+
+func (app *App) reloadOnSignal() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	for range sigChan {
+		log.Println("Received SIGHUP, reloading config")
+		app.Reload()
+	}
+}
+
+func (app *App) watchConfigFile(stop <-chan struct{}) {
+	path := app.loader.Path
+	interval := app.currentConfig().Reload.WatchInterval
+	if path == "" || interval <= 0 {
+		return
+	}
+
+	last, _ := os.ReadFile(path)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil || bytes.Equal(content, last) {
+				continue
+			}
+			last = content
+
+			log.Printf("Config file %s changed, reloading", path)
+			app.Reload()
+		}
+	}()
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	quotaservice "github.com/eightjhonydolly/05.12.2025/internal/domain/quota/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/config"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func checkTwoLinks(t *testing.T, handler http.Handler) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/check-links", bytes.NewReader([]byte(`{"links": ["not a url", "also not a url"]}`)))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestApp_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "api:\n  max_links: 1\n")
+
+	app, err := NewAppFromLoader(&config.Loader{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	if code := checkTwoLinks(t, app.server.Handler); code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected limit of one link, got status %d", code)
+	}
+
+	writeConfigFile(t, path, "api:\n  max_links: 5\nlogging:\n  level: warn\nserver:\n  port: \"9999\"\n")
+	if err := app.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if code := checkTwoLinks(t, app.server.Handler); code != http.StatusOK {
+		t.Errorf("Expected new limit to apply, got status %d", code)
+	}
+	if app.logLevel.Level() != slog.LevelWarn {
+		t.Errorf("Expected log level warn, got %s", app.logLevel.Level())
+	}
+	if app.currentConfig().Server.Port != "8080" {
+		t.Errorf("Expected server port to require a restart, got %s", app.currentConfig().Server.Port)
+	}
+
+	writeConfigFile(t, path, "api:\n  max_links: 1\nlogging:\n  level: loud\n")
+	if err := app.Reload(); err == nil {
+		t.Fatal("Expected invalid config to be rejected")
+	}
+
+	if code := checkTwoLinks(t, app.server.Handler); code != http.StatusOK {
+		t.Errorf("Expected previous config to stay active, got status %d", code)
+	}
+	if app.currentConfig().API.MaxLinks != 5 {
+		t.Errorf("Expected max links to stay 5, got %d", app.currentConfig().API.MaxLinks)
+	}
+}
+
+func TestApp_ReloadKeepsServices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "quota:\n  concurrent_batches: 1\nauth:\n  jwt:\n    jwks_url: http://idp.example/jwks\n    issuer: a\n")
+
+	app, err := NewAppFromLoader(&config.Loader{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	quotas, jwks := app.services.quotas, app.services.jwks
+
+	finish, err := quotas.Begin(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	writeConfigFile(t, path, "quota:\n  concurrent_batches: 1\n  links_per_batch: 5\nauth:\n  jwt:\n    jwks_url: http://idp.example/jwks\n    issuer: b\n")
+	if err := app.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if app.services.quotas != quotas {
+		t.Error("Expected the quota service to survive the reload")
+	}
+	if app.services.jwks != jwks {
+		t.Error("Expected the JWKS cache to survive the reload")
+	}
+
+	var exceeded *quotaservice.ExceededError
+	if _, err := app.services.quotas.Begin(context.Background(), 1); !errors.As(err, &exceeded) || exceeded.Quota != "concurrent_batches" {
+		t.Fatalf("Expected the running batch to still count, got %v", err)
+	}
+
+	finish(nil)
+	usage, err := app.services.quotas.Usage(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if usage.Tenant.Active != 0 || usage.Tenant.Quota.LinksPerBatch != 5 {
+		t.Errorf("Expected the batch to be released under the new quota, got %+v", usage.Tenant)
+	}
+}
+
+func TestApp_WatchConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "api:\n  max_links: 1\nreload:\n  watch_interval: 10ms\n")
+
+	app, err := NewAppFromLoader(&config.Loader{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	app.watchConfigFile(stop)
+
+	writeConfigFile(t, path, "api:\n  max_links: 3\nreload:\n  watch_interval: 10ms\n")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for app.currentConfig().API.MaxLinks != 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected file change to be picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
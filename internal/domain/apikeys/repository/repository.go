@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type KeyRepository interface {
+	Save(key *model.APIKey) error
+	GetByID(id string) (*model.APIKey, error)
+	GetByHash(hash string) (*model.APIKey, error)
+	List() ([]*model.APIKey, error)
+	Touch(id string, at time.Time) error
+}
+
+type InMemoryKeyRepository struct {
+	mu     sync.RWMutex
+	keys   map[string]*model.APIKey
+	byHash map[string]string
+}
+
+func NewInMemoryKeyRepository() *InMemoryKeyRepository {
+	return &InMemoryKeyRepository{
+		keys:   make(map[string]*model.APIKey),
+		byHash: make(map[string]string),
+	}
+}
+
+func (r *InMemoryKeyRepository) Save(key *model.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *key
+	r.keys[key.ID] = &stored
+	r.byHash[key.Hash] = key.ID
+	return nil
+}
+
+func (r *InMemoryKeyRepository) GetByID(id string) (*model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, nil
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (r *InMemoryKeyRepository) GetByHash(hash string) (*model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[r.byHash[hash]]
+	if !exists {
+		return nil, nil
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (r *InMemoryKeyRepository) List() ([]*model.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*model.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		copied := *key
+		keys = append(keys, &copied)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *InMemoryKeyRepository) Touch(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, exists := r.keys[id]; exists {
+		key.LastUsedAt = at
+	}
+	return nil
+}
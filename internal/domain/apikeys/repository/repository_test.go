@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func TestInMemoryKeyRepository_SaveAndGet(t *testing.T) {
+	repo := NewInMemoryKeyRepository()
+
+	key := &model.APIKey{ID: "k1", Hash: "h1", Scopes: []model.Scope{model.ScopeCheckWrite}}
+	if err := repo.Save(key); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	byID, _ := repo.GetByID("k1")
+	byHash, _ := repo.GetByHash("h1")
+	if byID == nil || byHash == nil || byID.ID != "k1" || byHash.ID != "k1" {
+		t.Fatalf("Expected key by ID and hash, got %v and %v", byID, byHash)
+	}
+
+	byID.Name = "changed"
+	if stored, _ := repo.GetByID("k1"); stored.Name != "" {
+		t.Error("Expected repository to return copies")
+	}
+
+	if missing, _ := repo.GetByHash("unknown"); missing != nil {
+		t.Errorf("Expected nil for unknown hash, got %v", missing)
+	}
+}
+
+func TestInMemoryKeyRepository_ListAndTouch(t *testing.T) {
+	repo := NewInMemoryKeyRepository()
+	now := time.Now()
+	repo.Save(&model.APIKey{ID: "second", Hash: "b", CreatedAt: now})
+	repo.Save(&model.APIKey{ID: "first", Hash: "a", CreatedAt: now.Add(-time.Hour)})
+
+	if err := repo.Touch("second", now); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+
+	keys, err := repo.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != "first" || keys[1].ID != "second" {
+		t.Fatalf("Expected keys ordered by creation, got %v", keys)
+	}
+	if !keys[1].LastUsedAt.Equal(now) {
+		t.Errorf("Expected last use to be recorded, got %v", keys[1].LastUsedAt)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+var (
+	ErrInvalidKey   = errors.New("invalid API key")
+	ErrKeyNotFound  = errors.New("API key not found")
+	ErrInvalidScope = errors.New("invalid scope")
+	ErrStaticKey    = errors.New("API key is defined in configuration")
+	ErrInvalidQuota = errors.New("invalid quota")
+)
+
+const (
+	keyPrefix        = "lck_"
+	keySecretBytes   = 32
+	visiblePrefixLen = 8
+)
+
+type KeyService interface {
+	Create(name, tenantID string, scopes []model.Scope, quota model.Quota) (*model.APIKey, string, error)
+	// List and Revoke see the keys of the tenant in ctx only.
+	List(ctx context.Context) ([]*model.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, secret string) (*model.Principal, error)
+	// Configure replaces the keys defined in configuration.
+	Configure(opts ...Option)
+}
+
+type Option func(*keyService)
+
+func WithStaticKeys(keys []*model.APIKey) Option {
+	return func(s *keyService) {
+		for _, key := range keys {
+			key.Static = true
+			s.static = append(s.static, key)
+		}
+	}
+}
+
+type keyService struct {
+	repo repository.KeyRepository
+
+	mu     sync.RWMutex
+	static []*model.APIKey
+}
+
+func NewKeyService(repo repository.KeyRepository, opts ...Option) KeyService {
+	s := &keyService{repo: repo}
+	s.Configure(opts...)
+	return s
+}
+
+func (s *keyService) Configure(opts ...Option) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.static = nil
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+func (s *keyService) staticKeys() []*model.APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.static
+}
+
+// HashKey returns the form in which keys are stored and configured.
+func HashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func ValidateScopes(scopes []model.Scope) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(model.Scopes, scope) {
+			return fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+	return nil
+}
+
+func (s *keyService) Create(name, tenantID string, scopes []model.Scope, quota model.Quota) (*model.APIKey, string, error) {
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, "", err
+	}
+	if quota.ChecksPerHour < 0 || quota.LinksPerBatch < 0 || quota.ConcurrentBatches < 0 {
+		return nil, "", fmt.Errorf("%w: limits must not be negative", ErrInvalidQuota)
+	}
+	if quota.StoredBatches != 0 {
+		return nil, "", fmt.Errorf("%w: stored batches are limited per tenant", ErrInvalidQuota)
+	}
+
+	buf := make([]byte, keySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	secret := keyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+
+	key := &model.APIKey{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Tenant:    tenantID,
+		Prefix:    secret[:len(keyPrefix)+visiblePrefixLen],
+		Hash:      HashKey(secret),
+		Scopes:    slices.Clone(scopes),
+		Quota:     quota,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Save(key); err != nil {
+		return nil, "", fmt.Errorf("failed to save key: %w", err)
+	}
+
+	log.Printf("Created API key %s (%s) for tenant %s with scopes %v", key.ID, key.Name, key.Tenant, key.Scopes)
+	return key, secret, nil
+}
+
+func (s *keyService) List(ctx context.Context) ([]*model.APIKey, error) {
+	stored, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	tenantID := tenant.FromContext(ctx)
+	var keys []*model.APIKey
+	for _, key := range append(slices.Clone(s.staticKeys()), stored...) {
+		if key.Tenant == tenantID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *keyService) Revoke(ctx context.Context, id string) error {
+	tenantID := tenant.FromContext(ctx)
+	for _, key := range s.staticKeys() {
+		if key.ID == id && key.Tenant == tenantID {
+			return ErrStaticKey
+		}
+	}
+
+	key, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get key: %w", err)
+	}
+	// Keys of other tenants are reported as missing so that their IDs
+	// cannot be probed.
+	if key == nil || key.Tenant != tenantID {
+		return ErrKeyNotFound
+	}
+	if key.Revoked() {
+		return nil
+	}
+
+	key.RevokedAt = time.Now()
+	if err := s.repo.Save(key); err != nil {
+		return fmt.Errorf("failed to save key: %w", err)
+	}
+
+	log.Printf("Revoked API key %s (%s)", key.ID, key.Name)
+	return nil
+}
+
+func (s *keyService) Authenticate(ctx context.Context, secret string) (*model.Principal, error) {
+	hash := HashKey(secret)
+	for _, key := range s.staticKeys() {
+		if key.Hash == hash {
+			return principal(key), nil
+		}
+	}
+
+	key, err := s.repo.GetByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key: %w", err)
+	}
+	if key == nil || key.Revoked() {
+		return nil, ErrInvalidKey
+	}
+
+	key.LastUsedAt = time.Now()
+	if err := s.repo.Touch(key.ID, key.LastUsedAt); err != nil {
+		log.Printf("Failed to record use of API key %s: %v", key.ID, err)
+	}
+	return principal(key), nil
+}
+
+func principal(key *model.APIKey) *model.Principal {
+	return &model.Principal{
+		Subject: key.ID,
+		Name:    key.Name,
+		Tenant:  key.Tenant,
+		Method:  model.AuthAPIKey,
+		Scopes:  key.Scopes,
+		Quota:   key.Quota,
+	}
+}
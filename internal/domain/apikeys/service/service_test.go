@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/apikeys/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+func TestKeyService_CreateAndAuthenticate(t *testing.T) {
+	repo := repository.NewInMemoryKeyRepository()
+	svc := NewKeyService(repo)
+
+	key, secret, err := svc.Create("ci", "team-a", []model.Scope{model.ScopeCheckWrite}, model.Quota{LinksPerBatch: 10})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, key.Prefix) || !strings.HasPrefix(secret, "lck_") {
+		t.Errorf("Expected secret %q to start with prefix %q", secret, key.Prefix)
+	}
+
+	stored, _ := repo.GetByID(key.ID)
+	if stored.Hash != HashKey(secret) || strings.Contains(stored.Hash, secret) {
+		t.Error("Expected only the hash of the key to be stored")
+	}
+
+	principal, err := svc.Authenticate(context.Background(), secret)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if principal.Subject != key.ID || principal.Method != model.AuthAPIKey || principal.Tenant != "team-a" {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+	if principal.Quota.LinksPerBatch != 10 {
+		t.Errorf("Expected quota of the key, got %+v", principal.Quota)
+	}
+	if !principal.HasScope(model.ScopeCheckWrite) || principal.HasScope(model.ScopeReportRead) {
+		t.Errorf("Unexpected scopes: %v", principal.Scopes)
+	}
+
+	if stored, _ := repo.GetByID(key.ID); stored.LastUsedAt.IsZero() {
+		t.Error("Expected last use to be recorded")
+	}
+
+	if _, err := svc.Authenticate(context.Background(), secret+"x"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected ErrInvalidKey for unknown key, got %v", err)
+	}
+}
+
+func TestKeyService_Revoke(t *testing.T) {
+	svc := NewKeyService(repository.NewInMemoryKeyRepository())
+	key, secret, _ := svc.Create("ci", "", []model.Scope{model.ScopeReportRead}, model.Quota{})
+	if key.Tenant != tenant.Default {
+		t.Errorf("Expected key without tenant to belong to %q, got %q", tenant.Default, key.Tenant)
+	}
+
+	if err := svc.Revoke(context.Background(), key.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := svc.Authenticate(context.Background(), secret); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected revoked key to be rejected, got %v", err)
+	}
+	if err := svc.Revoke(context.Background(), "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestKeyService_StaticKeys(t *testing.T) {
+	svc := NewKeyService(repository.NewInMemoryKeyRepository(), WithStaticKeys([]*model.APIKey{
+		{ID: "config-bootstrap", Name: "bootstrap", Tenant: tenant.Default, Hash: HashKey("bootstrap-secret"), Scopes: []model.Scope{model.ScopeAdmin}},
+	}))
+
+	principal, err := svc.Authenticate(context.Background(), "bootstrap-secret")
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if !principal.HasScope(model.ScopeReportRead) {
+		t.Error("Expected admin scope to grant every scope")
+	}
+
+	keys, _ := svc.List(context.Background())
+	if len(keys) != 1 || !keys[0].Static {
+		t.Errorf("Expected static key in list, got %v", keys)
+	}
+	if err := svc.Revoke(context.Background(), "config-bootstrap"); !errors.Is(err, ErrStaticKey) {
+		t.Errorf("Expected ErrStaticKey, got %v", err)
+	}
+}
+
+func TestKeyService_TenantScope(t *testing.T) {
+	svc := NewKeyService(repository.NewInMemoryKeyRepository(), WithStaticKeys([]*model.APIKey{
+		{ID: "config-b", Name: "b", Tenant: "team-b", Hash: HashKey("b-secret"), Scopes: []model.Scope{model.ScopeAdmin}},
+	}))
+	teamA := tenant.WithTenant(context.Background(), "team-a")
+	teamB := tenant.WithTenant(context.Background(), "team-b")
+
+	key, _, _ := svc.Create("ci", "team-a", []model.Scope{model.ScopeCheckWrite}, model.Quota{})
+
+	if keys, _ := svc.List(teamA); len(keys) != 1 || keys[0].ID != key.ID {
+		t.Errorf("Expected only the key of team-a, got %v", keys)
+	}
+	if keys, _ := svc.List(teamB); len(keys) != 1 || keys[0].ID != "config-b" {
+		t.Errorf("Expected only the key of team-b, got %v", keys)
+	}
+
+	if err := svc.Revoke(teamB, key.ID); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected key of another tenant not to be found, got %v", err)
+	}
+	if err := svc.Revoke(teamA, "config-b"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected static key of another tenant not to be found, got %v", err)
+	}
+	if err := svc.Revoke(teamA, key.ID); err != nil {
+		t.Errorf("Revoke failed: %v", err)
+	}
+}
+
+func TestKeyService_InvalidScopes(t *testing.T) {
+	svc := NewKeyService(repository.NewInMemoryKeyRepository())
+
+	for _, scopes := range [][]model.Scope{nil, {"check:read"}} {
+		if _, _, err := svc.Create("ci", "", scopes, model.Quota{}); !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("Create(%v): expected ErrInvalidScope, got %v", scopes, err)
+		}
+	}
+}
+
+func TestKeyService_InvalidQuota(t *testing.T) {
+	svc := NewKeyService(repository.NewInMemoryKeyRepository())
+	scopes := []model.Scope{model.ScopeCheckWrite}
+
+	for _, quota := range []model.Quota{{ChecksPerHour: -1}, {StoredBatches: 5}} {
+		if _, _, err := svc.Create("ci", "", scopes, quota); !errors.Is(err, ErrInvalidQuota) {
+			t.Errorf("Create(%+v): expected ErrInvalidQuota, got %v", quota, err)
+		}
+	}
+}
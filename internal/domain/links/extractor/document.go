@@ -0,0 +1,174 @@
+package extractor
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type DocumentLink struct {
+	URL  string
+	Line int
+}
+
+var (
+	markdownInlineLink    = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'(][^)]*["')])?\s*\)`)
+	markdownReferenceLink = regexp.MustCompile(`^\s{0,3}\[([^\]]+)\]:\s*<?(\S+?)>?(?:\s+["'(].*["')])?\s*$`)
+	markdownReferenceUse  = regexp.MustCompile(`\[([^\]]+)\](?:\[([^\]]*)\])?`)
+	markdownAutolink      = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	htmlAttribute         = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	bareURL               = regexp.MustCompile(`https?://[^\s<>"'\x60]+`)
+)
+
+func ExtractDocumentLinks(name string, content []byte) []DocumentLink {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	var patterns []func(line string) []string
+	var refs *markdownReferences
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		refs = newMarkdownReferences(lines)
+		patterns = []func(string) []string{refs.links, htmlLinks, bareLinks}
+	case ".html", ".htm":
+		patterns = []func(string) []string{htmlLinks, bareLinks}
+	default:
+		patterns = []func(string) []string{bareLinks}
+	}
+
+	var links []DocumentLink
+	for i, line := range lines {
+		// Used references are reported where they are used.
+		if refs != nil && refs.usedDefinition(line) {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, pattern := range patterns {
+			for _, link := range pattern(line) {
+				if !isAbsoluteHTTP(link) || seen[link] {
+					continue
+				}
+				seen[link] = true
+				links = append(links, DocumentLink{URL: link, Line: i + 1})
+			}
+		}
+	}
+	return links
+}
+
+// markdownReferences are the link reference definitions of a document and
+// the labels used by reference links.
+type markdownReferences struct {
+	urls map[string]string
+	used map[string]bool
+}
+
+func newMarkdownReferences(lines []string) *markdownReferences {
+	refs := &markdownReferences{urls: make(map[string]string), used: make(map[string]bool)}
+	for _, line := range lines {
+		if m := markdownReferenceLink.FindStringSubmatch(line); m != nil {
+			if label := referenceLabel(m[1]); refs.urls[label] == "" {
+				refs.urls[label] = m[2]
+			}
+		}
+	}
+	for _, line := range lines {
+		for _, label := range referenceUses(line) {
+			if _, ok := refs.urls[label]; ok {
+				refs.used[label] = true
+			}
+		}
+	}
+	return refs
+}
+
+func (r *markdownReferences) usedDefinition(line string) bool {
+	m := markdownReferenceLink.FindStringSubmatch(line)
+	return m != nil && r.used[referenceLabel(m[1])]
+}
+
+func (r *markdownReferences) links(line string) []string {
+	var links []string
+	for _, m := range markdownInlineLink.FindAllStringSubmatch(line, -1) {
+		links = append(links, m[1])
+	}
+	for _, label := range referenceUses(line) {
+		if url, ok := r.urls[label]; ok {
+			links = append(links, url)
+		}
+	}
+	if m := markdownReferenceLink.FindStringSubmatch(line); m != nil {
+		links = append(links, m[2])
+	}
+	for _, m := range markdownAutolink.FindAllStringSubmatch(line, -1) {
+		links = append(links, m[1])
+	}
+	return links
+}
+
+// referenceUses returns the labels of the full ([text][label]), collapsed
+// ([label][]) and shortcut ([label]) reference links on line.
+func referenceUses(line string) []string {
+	var labels []string
+	for _, m := range markdownReferenceUse.FindAllStringSubmatchIndex(line, -1) {
+		if m[1] < len(line) && (line[m[1]] == '(' || line[m[1]] == ':') {
+			continue
+		}
+		label := line[m[2]:m[3]]
+		if m[4] >= 0 && m[5] > m[4] {
+			label = line[m[4]:m[5]]
+		}
+		labels = append(labels, referenceLabel(label))
+	}
+	return labels
+}
+
+// referenceLabel normalizes a label the way Markdown matches them: case
+// insensitively with collapsed whitespace.
+func referenceLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+func htmlLinks(line string) []string {
+	var links []string
+	for _, m := range htmlAttribute.FindAllStringSubmatch(line, -1) {
+		links = append(links, m[1]+m[2])
+	}
+	return links
+}
+
+func bareLinks(line string) []string {
+	var links []string
+	for _, link := range bareURL.FindAllString(line, -1) {
+		links = append(links, trimTrailingPunctuation(link))
+	}
+	return links
+}
+
+func trimTrailingPunctuation(link string) string {
+	for len(link) > 0 {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*_~", last) >= 0:
+			link = link[:len(link)-1]
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+			link = link[:len(link)-1]
+		case last == ']' && strings.Count(link, "[") < strings.Count(link, "]"):
+			link = link[:len(link)-1]
+		default:
+			return link
+		}
+	}
+	return link
+}
+
+func isAbsoluteHTTP(link string) bool {
+	lower := strings.ToLower(link)
+	return (strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")) && len(link) > len("https://")
+}
@@ -0,0 +1,76 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractDocumentLinks_Markdown(t *testing.T) {
+	doc := "# Title\n" +
+		"See [the guide](https://example.com/guide \"Guide\") and ![logo](https://example.com/logo.png).\n" +
+		"Use [the API][api] or <https://example.com/auto>.\n" +
+		"\n" +
+		"[api]: https://api.example.com/v1\n" +
+		"Plain https://example.com/plain, and (https://example.com/paren).\n" +
+		"<a href=\"https://example.com/html\">html</a> [relative](./other.md)\n"
+
+	links := ExtractDocumentLinks("README.md", []byte(doc))
+
+	expected := []DocumentLink{
+		{URL: "https://example.com/guide", Line: 2},
+		{URL: "https://example.com/logo.png", Line: 2},
+		{URL: "https://api.example.com/v1", Line: 3},
+		{URL: "https://example.com/auto", Line: 3},
+		{URL: "https://example.com/plain", Line: 6},
+		{URL: "https://example.com/paren", Line: 6},
+		{URL: "https://example.com/html", Line: 7},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestExtractDocumentLinks_MarkdownReferences(t *testing.T) {
+	doc := "Read the [docs][Guide Page] first.\n" +
+		"\n" +
+		"Then the [changelog] and [faq][].\n" +
+		"\n" +
+		"[guide   page]: https://example.com/guide\n" +
+		"[changelog]: <https://example.com/changes> \"Changes\"\n" +
+		"[faq]: https://example.com/faq\n" +
+		"[unused]: https://example.com/unused\n"
+
+	links := ExtractDocumentLinks("README.md", []byte(doc))
+
+	expected := []DocumentLink{
+		{URL: "https://example.com/guide", Line: 1},
+		{URL: "https://example.com/changes", Line: 3},
+		{URL: "https://example.com/faq", Line: 3},
+		{URL: "https://example.com/unused", Line: 8},
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestExtractDocumentLinks_HTML(t *testing.T) {
+	doc := "<html>\n<img src='https://cdn.example.com/a.png'>\n<a href=\"/local\">local</a>\n</html>\n"
+
+	links := ExtractDocumentLinks("index.HTML", []byte(doc))
+
+	expected := []DocumentLink{{URL: "https://cdn.example.com/a.png", Line: 2}}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestExtractDocumentLinks_PlainText(t *testing.T) {
+	doc := "first line\nvisit http://example.com/path?q=1.\n"
+
+	links := ExtractDocumentLinks("notes.txt", []byte(doc))
+
+	expected := []DocumentLink{{URL: "http://example.com/path?q=1", Line: 2}}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
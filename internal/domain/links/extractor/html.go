@@ -0,0 +1,162 @@
+package extractor
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var linkAttributes = map[string]string{
+	"a":      "href",
+	"img":    "src",
+	"link":   "href",
+	"script": "src",
+}
+
+func ExtractHTMLLinks(r io.Reader, base *url.URL) ([]string, error) {
+	var links []string
+	seen := make(map[string]bool)
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return links, err
+			}
+			return links, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data == "base" {
+				if href := attr(token, "href"); href != "" {
+					if resolved, err := base.Parse(href); err == nil {
+						base = resolved
+					}
+				}
+				continue
+			}
+
+			name, ok := linkAttributes[token.Data]
+			if !ok {
+				continue
+			}
+			link, ok := ResolveLink(base, attr(token, name))
+			if !ok || seen[link] {
+				continue
+			}
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+}
+
+func ResolveLink(base *url.URL, ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return "", false
+	}
+
+	u, err := base.Parse(ref)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), true
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func ExtractHTMLAnchors(r io.Reader) (map[string]bool, error) {
+	anchors := make(map[string]bool)
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return anchors, err
+			}
+			return anchors, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if id := attr(token, "id"); id != "" {
+				anchors[id] = true
+			}
+			if token.Data == "a" {
+				if name := attr(token, "name"); name != "" {
+					anchors[name] = true
+				}
+			}
+		}
+	}
+}
+
+var subresourceAttributes = map[string]string{
+	"img":    "src",
+	"script": "src",
+	"link":   "href",
+	"iframe": "src",
+	"audio":  "src",
+	"video":  "src",
+	"source": "src",
+	"embed":  "src",
+	"object": "data",
+	"form":   "action",
+}
+
+func ExtractMixedContent(r io.Reader) ([]string, error) {
+	var insecure []string
+	seen := make(map[string]bool)
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return insecure, err
+			}
+			return insecure, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			name, ok := subresourceAttributes[token.Data]
+			if !ok {
+				continue
+			}
+			if token.Data == "link" && !isSubresourceLink(attr(token, "rel")) {
+				continue
+			}
+			ref := strings.TrimSpace(attr(token, name))
+			if strings.HasPrefix(strings.ToLower(ref), "http://") && !seen[ref] {
+				seen[ref] = true
+				insecure = append(insecure, ref)
+			}
+		}
+	}
+}
+
+func isSubresourceLink(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		switch value {
+		case "stylesheet", "icon", "preload", "modulepreload", "manifest":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package extractor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractHTMLLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/docs/index.html")
+
+	page := `<html><head>
+		<link rel="stylesheet" href="/static/site.css">
+		<script src="app.js"></script>
+	</head><body>
+		<a href="guide.html#install">Guide</a>
+		<a href="guide.html">Guide again</a>
+		<a href="#top">Top</a>
+		<a href="mailto:team@example.com">Mail</a>
+		<a href="javascript:void(0)">Noop</a>
+		<img src="https://cdn.example.org/logo.png"/>
+	</body></html>`
+
+	links, err := ExtractHTMLLinks(strings.NewReader(page), base)
+	if err != nil {
+		t.Fatalf("ExtractHTMLLinks failed: %v", err)
+	}
+
+	expected := []string{
+		"https://example.com/static/site.css",
+		"https://example.com/docs/app.js",
+		"https://example.com/docs/guide.html",
+		"https://cdn.example.org/logo.png",
+	}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestExtractHTMLLinks_BaseTag(t *testing.T) {
+	base, _ := url.Parse("https://example.com/a/")
+
+	page := `<base href="https://mirror.example.com/b/"><a href="page">Page</a>`
+
+	links, err := ExtractHTMLLinks(strings.NewReader(page), base)
+	if err != nil {
+		t.Fatalf("ExtractHTMLLinks failed: %v", err)
+	}
+
+	if len(links) != 1 || links[0] != "https://mirror.example.com/b/page" {
+		t.Errorf("Expected link resolved against <base>, got %v", links)
+	}
+}
+
+func TestExtractHTMLAnchors(t *testing.T) {
+	page := `<h2 id="install">Install</h2><a name="legacy"></a><div name="ignored"></div><p id="">empty</p>`
+
+	anchors, err := ExtractHTMLAnchors(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("ExtractHTMLAnchors failed: %v", err)
+	}
+
+	expected := map[string]bool{"install": true, "legacy": true}
+	if !reflect.DeepEqual(anchors, expected) {
+		t.Errorf("Expected %v, got %v", expected, anchors)
+	}
+}
+
+func TestExtractMixedContent(t *testing.T) {
+	page := `<link rel="stylesheet" href="http://cdn.example.com/site.css">
+		<link rel="canonical" href="http://example.com/page">
+		<script src="https://cdn.example.com/app.js"></script>
+		<img src="http://images.example.com/a.png"><img src="http://images.example.com/a.png">
+		<a href="http://example.com/plain-link">navigation is not mixed content</a>`
+
+	insecure, err := ExtractMixedContent(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("ExtractMixedContent failed: %v", err)
+	}
+
+	expected := []string{"http://cdn.example.com/site.css", "http://images.example.com/a.png"}
+	if !reflect.DeepEqual(insecure, expected) {
+		t.Errorf("Expected %v, got %v", expected, insecure)
+	}
+}
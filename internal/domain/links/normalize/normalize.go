@@ -0,0 +1,192 @@
+package normalize
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalid = errors.New("invalid URL")
+
+var (
+	schemePrefix = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*):`)
+	portPrefix   = regexp.MustCompile(`^\d+(?:[/?#]|$)`)
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+var trackingParams = map[string]bool{
+	"gclid":   true,
+	"dclid":   true,
+	"fbclid":  true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"_gl":     true,
+}
+
+type Options struct {
+	StripTrackingParams bool
+}
+
+type URL struct {
+	*url.URL
+	ImplicitScheme bool
+}
+
+func Normalize(raw string, opts Options) (*URL, error) {
+	raw = strings.TrimFunc(raw, unicode.IsSpace)
+	if raw == "" {
+		return nil, fmt.Errorf("%w: empty value", ErrInvalid)
+	}
+	if i := strings.IndexFunc(raw, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }); i >= 0 {
+		return nil, fmt.Errorf("%w: contains whitespace or control characters", ErrInvalid)
+	}
+
+	implicit := false
+	if m := schemePrefix.FindStringSubmatch(raw); m == nil || portPrefix.MatchString(raw[len(m[0]):]) {
+		raw = "http://" + strings.TrimPrefix(raw, "//")
+		implicit = true
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, unwrapURLError(err))
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[u.Scheme]; !ok {
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalid, u.Scheme)
+	}
+	if u.Opaque != "" {
+		return nil, fmt.Errorf("%w: missing // after scheme", ErrInvalid)
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	port := u.Port()
+	if port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("%w: invalid port %q", ErrInvalid, port)
+		}
+		port = strconv.Itoa(n)
+	}
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	// Dot segments are removed from the escaped path so that escapes such as
+	// %2F and %2E keep addressing the same resource.
+	escaped := removeDotSegments(u.EscapedPath())
+	if escaped == "" {
+		escaped = "/"
+	}
+	if u.Path, err = url.PathUnescape(escaped); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	u.RawPath = escaped
+
+	if opts.StripTrackingParams && u.RawQuery != "" {
+		u.RawQuery = stripTrackingParams(u.RawQuery)
+	}
+	u.ForceQuery = false
+
+	return &URL{URL: u, ImplicitScheme: implicit}, nil
+}
+
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", fmt.Errorf("%w: missing host", ErrInvalid)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	host = strings.TrimSuffix(host, ".")
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid host %q: %v", ErrInvalid, host, err)
+	}
+	return strings.ToLower(ascii), nil
+}
+
+// removeDotSegments implements RFC 3986 section 5.2.4.
+func removeDotSegments(p string) string {
+	if !strings.Contains(p, ".") {
+		return p
+	}
+
+	var out []string
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch segment {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, segment)
+		}
+	}
+
+	result := strings.Join(out, "/")
+	if strings.HasPrefix(p, "/") && !strings.HasPrefix(result, "/") {
+		result = "/" + result
+	}
+	return result
+}
+
+func stripTrackingParams(rawQuery string) string {
+	var kept []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		key, _, _ := strings.Cut(pair, "=")
+		if name, err := url.QueryUnescape(key); err == nil {
+			name = strings.ToLower(name)
+			if strings.HasPrefix(name, "utm_") || trackingParams[name] {
+				continue
+			}
+		}
+		kept = append(kept, pair)
+	}
+	return strings.Join(kept, "&")
+}
+
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
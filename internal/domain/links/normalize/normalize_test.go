@@ -0,0 +1,79 @@
+package normalize
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+		implicit bool
+	}{
+		{"  Example.COM  ", "http://example.com/", true},
+		{"example.com:8080/a", "http://example.com:8080/a", true},
+		{"HTTP://Example.com:80/a/./b/../c", "http://example.com/a/c", false},
+		{"https://example.com:443", "https://example.com/", false},
+		{"https://example.com:8443/x/..", "https://example.com:8443/", false},
+		{"https://münchen.de/straße", "https://xn--mnchen-3ya.de/stra%C3%9Fe", false},
+		{"https://EXAMPLE.com./docs/#Install", "https://example.com/docs/#Install", false},
+		{"http://[::1]:80/", "http://[::1]/", false},
+		{"//cdn.example.com/lib.js", "http://cdn.example.com/lib.js", true},
+		{"https://example.com/search?q=go&utm_source=mail", "https://example.com/search?q=go&utm_source=mail", false},
+		{"https://api.example.com/v1/files/a%2Fb", "https://api.example.com/v1/files/a%2Fb", false},
+		{"https://example.com/a/%2E%2E/b", "https://example.com/a/%2E%2E/b", false},
+		{"https://example.com/a%20b/../c", "https://example.com/c", false},
+	}
+
+	for _, tt := range tests {
+		u, err := Normalize(tt.raw, Options{})
+		if err != nil {
+			t.Errorf("Normalize(%q) failed: %v", tt.raw, err)
+			continue
+		}
+		if u.String() != tt.expected {
+			t.Errorf("Normalize(%q) = %q, expected %q", tt.raw, u.String(), tt.expected)
+		}
+		if u.ImplicitScheme != tt.implicit {
+			t.Errorf("Normalize(%q) implicit scheme = %v, expected %v", tt.raw, u.ImplicitScheme, tt.implicit)
+		}
+	}
+}
+
+func TestNormalize_StripTrackingParams(t *testing.T) {
+	u, err := Normalize("https://example.com/page?id=7&utm_source=news&UTM_Medium=mail&gclid=abc&fbclid=x", Options{StripTrackingParams: true})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	if u.String() != "https://example.com/page?id=7" {
+		t.Errorf("Expected tracking parameters to be stripped, got %s", u.String())
+	}
+
+	u, _ = Normalize("https://example.com/?utm_source=news", Options{StripTrackingParams: true})
+	if u.String() != "https://example.com/" {
+		t.Errorf("Expected empty query to be removed, got %s", u.String())
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"   ",
+		"exa mple.com",
+		"ftp://example.com/file",
+		"mailto:team@example.com",
+		"http://",
+		"http://example.com:99999/",
+		"http://exa_mple..com/",
+		"http:example.com",
+		"http://[::1/",
+	}
+
+	for _, raw := range invalid {
+		if _, err := Normalize(raw, Options{}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Normalize(%q) expected ErrInvalid, got %v", raw, err)
+		}
+	}
+}
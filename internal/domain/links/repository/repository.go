@@ -6,63 +6,102 @@ import (
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
 )
 
+// LinkRepository stores batches per tenant; a batch is only visible to the
+// tenant that created it.
 type LinkRepository interface {
 	SaveBatch(batch *model.LinkBatch) error
-	GetBatch(id int) (*model.LinkBatch, error)
-	GetBatches(ids []int) ([]*model.LinkBatch, error)
-	GetNextID() int
+	GetBatch(tenant, id string) (*model.LinkBatch, error)
+	GetBatches(tenant string, ids []string) ([]*model.LinkBatch, error)
+	// GetBatchesByNumber looks batches up by their number within the tenant.
+	GetBatchesByNumber(tenant string, numbers []int) ([]*model.LinkBatch, error)
+	CountBatches(tenant string) (int, error)
+}
+
+type batchKey struct {
+	tenant string
+	id     string
+}
+
+type batchNumberKey struct {
+	tenant string
+	number int
 }
 
 type InMemoryLinkRepository struct {
-	mu      sync.RWMutex
-	batches map[int]*model.LinkBatch
-	nextID  int
+	mu       sync.RWMutex
+	batches  map[batchKey]*model.LinkBatch
+	numbers  map[batchNumberKey]*model.LinkBatch
+	lastNums map[string]int
 }
 
 func NewInMemoryLinkRepository() *InMemoryLinkRepository {
 	return &InMemoryLinkRepository{
-		batches: make(map[int]*model.LinkBatch),
-		nextID:  1,
+		batches:  make(map[batchKey]*model.LinkBatch),
+		numbers:  make(map[batchNumberKey]*model.LinkBatch),
+		lastNums: make(map[string]int),
 	}
 }
 
+// SaveBatch numbers new batches sequentially within their tenant.
 func (r *InMemoryLinkRepository) SaveBatch(batch *model.LinkBatch) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.batches[batch.ID] = batch
+	if batch.Number == 0 {
+		r.lastNums[batch.Tenant]++
+		batch.Number = r.lastNums[batch.Tenant]
+	}
+	r.batches[batchKey{batch.Tenant, batch.ID}] = batch
+	r.numbers[batchNumberKey{batch.Tenant, batch.Number}] = batch
 	return nil
 }
 
-func (r *InMemoryLinkRepository) GetBatch(id int) (*model.LinkBatch, error) {
+func (r *InMemoryLinkRepository) GetBatch(tenant, id string) (*model.LinkBatch, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	batch, exists := r.batches[id]
+	batch, exists := r.batches[batchKey{tenant, id}]
 	if !exists {
 		return nil, nil
 	}
 	return batch, nil
 }
 
-func (r *InMemoryLinkRepository) GetBatches(ids []int) ([]*model.LinkBatch, error) {
+func (r *InMemoryLinkRepository) GetBatches(tenant string, ids []string) ([]*model.LinkBatch, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var batches []*model.LinkBatch
 	for _, id := range ids {
-		if batch, exists := r.batches[id]; exists {
+		if batch, exists := r.batches[batchKey{tenant, id}]; exists {
 			batches = append(batches, batch)
 		}
 	}
 	return batches, nil
 }
 
-func (r *InMemoryLinkRepository) GetNextID() int {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+func (r *InMemoryLinkRepository) GetBatchesByNumber(tenant string, numbers []int) ([]*model.LinkBatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	id := r.nextID
-	r.nextID++
-	return id
+	var batches []*model.LinkBatch
+	for _, number := range numbers {
+		if batch, exists := r.numbers[batchNumberKey{tenant, number}]; exists {
+			batches = append(batches, batch)
+		}
+	}
+	return batches, nil
+}
+
+func (r *InMemoryLinkRepository) CountBatches(tenant string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for key := range r.batches {
+		if key.tenant == tenant {
+			count++
+		}
+	}
+	return count, nil
 }
@@ -10,7 +10,8 @@ func TestInMemoryLinkRepository_SaveAndGetBatch(t *testing.T) {
 	repo := NewInMemoryLinkRepository()
 
 	batch := &model.LinkBatch{
-		ID: 1,
+		ID:     "b1",
+		Tenant: "team-a",
 		Links: []model.LinkCheck{
 			{URL: "google.com", Status: model.StatusAvailable},
 		},
@@ -21,7 +22,7 @@ func TestInMemoryLinkRepository_SaveAndGetBatch(t *testing.T) {
 		t.Fatalf("SaveBatch failed: %v", err)
 	}
 
-	retrieved, err := repo.GetBatch(1)
+	retrieved, err := repo.GetBatch("team-a", "b1")
 	if err != nil {
 		t.Fatalf("GetBatch failed: %v", err)
 	}
@@ -30,36 +31,40 @@ func TestInMemoryLinkRepository_SaveAndGetBatch(t *testing.T) {
 		t.Fatal("Expected batch, got nil")
 	}
 
-	if retrieved.ID != 1 {
-		t.Errorf("Expected ID 1, got %d", retrieved.ID)
+	if retrieved.ID != "b1" {
+		t.Errorf("Expected ID b1, got %s", retrieved.ID)
 	}
 }
 
-func TestInMemoryLinkRepository_GetNextID(t *testing.T) {
+func TestInMemoryLinkRepository_TenantIsolation(t *testing.T) {
 	repo := NewInMemoryLinkRepository()
 
-	id1 := repo.GetNextID()
-	id2 := repo.GetNextID()
+	repo.SaveBatch(&model.LinkBatch{ID: "b1", Tenant: "team-a"})
 
-	if id1 != 1 {
-		t.Errorf("Expected first ID to be 1, got %d", id1)
+	if batch, _ := repo.GetBatch("team-b", "b1"); batch != nil {
+		t.Error("Expected batch of another tenant to be hidden")
 	}
-
-	if id2 != 2 {
-		t.Errorf("Expected second ID to be 2, got %d", id2)
+	if batches, _ := repo.GetBatches("team-b", []string{"b1"}); len(batches) != 0 {
+		t.Errorf("Expected no batches for another tenant, got %d", len(batches))
+	}
+	if count, _ := repo.CountBatches("team-b"); count != 0 {
+		t.Errorf("Expected no batches counted for another tenant, got %d", count)
+	}
+	if count, _ := repo.CountBatches("team-a"); count != 1 {
+		t.Errorf("Expected 1 batch for team-a, got %d", count)
 	}
 }
 
 func TestInMemoryLinkRepository_GetBatches(t *testing.T) {
 	repo := NewInMemoryLinkRepository()
 
-	batch1 := &model.LinkBatch{ID: 1}
-	batch2 := &model.LinkBatch{ID: 2}
+	batch1 := &model.LinkBatch{ID: "b1", Tenant: "team-a"}
+	batch2 := &model.LinkBatch{ID: "b2", Tenant: "team-a"}
 
 	repo.SaveBatch(batch1)
 	repo.SaveBatch(batch2)
 
-	batches, err := repo.GetBatches([]int{1, 2})
+	batches, err := repo.GetBatches("team-a", []string{"b1", "b2"})
 	if err != nil {
 		t.Fatalf("GetBatches failed: %v", err)
 	}
@@ -67,4 +72,24 @@ func TestInMemoryLinkRepository_GetBatches(t *testing.T) {
 	if len(batches) != 2 {
 		t.Errorf("Expected 2 batches, got %d", len(batches))
 	}
-}
\ No newline at end of file
+}
+
+func TestInMemoryLinkRepository_NumbersPerTenant(t *testing.T) {
+	repo := NewInMemoryLinkRepository()
+
+	a1 := &model.LinkBatch{ID: "a1", Tenant: "team-a"}
+	a2 := &model.LinkBatch{ID: "a2", Tenant: "team-a"}
+	b1 := &model.LinkBatch{ID: "b1", Tenant: "team-b"}
+	for _, batch := range []*model.LinkBatch{a1, b1, a2} {
+		repo.SaveBatch(batch)
+	}
+
+	if a1.Number != 1 || a2.Number != 2 || b1.Number != 1 {
+		t.Errorf("Expected numbers 1, 2 and 1, got %d, %d and %d", a1.Number, a2.Number, b1.Number)
+	}
+
+	batches, _ := repo.GetBatchesByNumber("team-b", []int{1, 2})
+	if len(batches) != 1 || batches[0].ID != "b1" {
+		t.Errorf("Expected only the batch of team-b, got %v", batches)
+	}
+}
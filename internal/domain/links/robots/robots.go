@@ -0,0 +1,134 @@
+package robots
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type rule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+type group struct {
+	agents []string
+	rules  []rule
+}
+
+type Robots struct {
+	groups   []*group
+	Sitemaps []string
+}
+
+func Parse(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+
+	var current *group
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || !lastWasAgent {
+				current = &group{}
+				robots.groups = append(robots.groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		case "allow", "disallow":
+			if current != nil && value != "" {
+				current.rules = append(current.rules, rule{
+					allow:   key == "allow",
+					pattern: value,
+					re:      compilePattern(value),
+				})
+			}
+		case "sitemap":
+			if value != "" {
+				robots.Sitemaps = append(robots.Sitemaps, value)
+			}
+		}
+		lastWasAgent = false
+	}
+
+	return robots, scanner.Err()
+}
+
+func (r *Robots) Allowed(userAgent, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+
+	g := r.groupFor(strings.ToLower(userAgent))
+	if g == nil {
+		return true
+	}
+
+	var best *rule
+	for i := range g.rules {
+		candidate := &g.rules[i]
+		if !candidate.re.MatchString(path) {
+			continue
+		}
+		if best == nil || len(candidate.pattern) > len(best.pattern) ||
+			(len(candidate.pattern) == len(best.pattern) && candidate.allow) {
+			best = candidate
+		}
+	}
+	return best == nil || best.allow
+}
+
+func (r *Robots) groupFor(userAgent string) *group {
+	var wildcard, match *group
+	matchLen := 0
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent == "*" {
+				if wildcard == nil {
+					wildcard = g
+				}
+				continue
+			}
+			if userAgent != "" && strings.Contains(userAgent, agent) && len(agent) > matchLen {
+				match = g
+				matchLen = len(agent)
+			}
+		}
+	}
+	if match != nil {
+		return match
+	}
+	return wildcard
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
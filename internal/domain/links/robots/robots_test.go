@@ -0,0 +1,61 @@
+package robots
+
+import (
+	"strings"
+	"testing"
+)
+
+const robotsTxt = `# example
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: LinkChecker
+User-agent: OtherBot
+Disallow: /
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestRobots_Allowed(t *testing.T) {
+	robots, err := Parse(strings.NewReader(robotsTxt))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		agent   string
+		path    string
+		allowed bool
+	}{
+		{"", "/", true},
+		{"", "/private/secret", false},
+		{"", "/private/public/page", true},
+		{"", "/files/doc.pdf", false},
+		{"", "/files/doc.pdf?download=1", true},
+		{"LinkChecker/1.0", "/anything", false},
+		{"Go-http-client/1.1", "/private/x", false},
+	}
+
+	for _, tt := range tests {
+		if got := robots.Allowed(tt.agent, tt.path); got != tt.allowed {
+			t.Errorf("Allowed(%q, %q) = %v, expected %v", tt.agent, tt.path, got, tt.allowed)
+		}
+	}
+
+	if len(robots.Sitemaps) != 1 || robots.Sitemaps[0] != "https://example.com/sitemap.xml" {
+		t.Errorf("Expected sitemap directive, got %v", robots.Sitemaps)
+	}
+}
+
+func TestRobots_EmptyAllowsEverything(t *testing.T) {
+	robots, err := Parse(strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !robots.Allowed("", "/anything") {
+		t.Error("Expected empty robots.txt to allow everything")
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/extractor"
+)
+
+func shouldValidateFragment(fragment string) bool {
+	if fragment == "" || strings.EqualFold(fragment, "top") {
+		return false
+	}
+	// Text fragments and client-side routes are not element anchors.
+	return !strings.HasPrefix(fragment, ":~:") && !strings.HasPrefix(fragment, "/") && !strings.HasPrefix(fragment, "!")
+}
+
+func hasAnchor(contentType string, body []byte, fragment string) (bool, error) {
+	if !isHTML(contentType) {
+		return true, nil
+	}
+
+	anchors, err := extractor.ExtractHTMLAnchors(bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	return anchors[fragment], nil
+}
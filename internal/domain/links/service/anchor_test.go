@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func TestLinkService_CheckLinks_AnchorValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<h2 id="install">Install</h2><a name="usage"></a>`)
+	}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	urls := []string{
+		server.URL + "/page#install",
+		server.URL + "/page#usage",
+		server.URL + "/page#removed",
+		server.URL + "/page#top",
+		server.URL + "/page",
+	}
+	expected := []model.LinkStatus{
+		model.StatusAvailable,
+		model.StatusAvailable,
+		model.StatusBrokenAnchor,
+		model.StatusAvailable,
+		model.StatusAvailable,
+	}
+
+	batch, err := service.CheckLinks(context.Background(), urls, WithAnchorValidation())
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	for i, link := range batch.Links {
+		if link.Status != expected[i] {
+			t.Errorf("Expected %s to be %s, got %s", link.URL, expected[i], link.Status)
+		}
+	}
+
+	batch, err = service.CheckLinks(context.Background(), []string{server.URL + "/page#removed"})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusAvailable {
+		t.Errorf("Expected anchors to be ignored without the option, got %s", batch.Links[0].Status)
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+var jsonPathSegment = regexp.MustCompile(`^([^\[\]]*)((?:\[\d+\])*)$`)
+
+func validateAssertions(assertions map[string][]model.Assertion) error {
+	for link, list := range assertions {
+		for _, a := range list {
+			if err := validateAssertion(a); err != nil {
+				return fmt.Errorf("%w for %s: %v", ErrInvalidAssertion, link, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateAssertion(a model.Assertion) error {
+	switch a.Type {
+	case model.AssertStatusCode:
+		if len(a.StatusCodes) == 0 {
+			return errors.New("status_code requires at least one code")
+		}
+	case model.AssertBodyContains, model.AssertBodyNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%s requires a value", a.Type)
+		}
+	case model.AssertBodyMatches:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("body_matches has invalid pattern: %v", err)
+		}
+	case model.AssertJSONPathEquals:
+		if _, err := parseJSONPath(a.JSONPath); err != nil {
+			return err
+		}
+	case model.AssertHeaderPresent, model.AssertHeaderEquals:
+		if a.Header == "" {
+			return fmt.Errorf("%s requires a header name", a.Type)
+		}
+	case model.AssertMaxResponseTime:
+		if a.MaxResponseTime <= 0 {
+			return errors.New("max_response_time must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+	return nil
+}
+
+func hasStatusAssertion(assertions []model.Assertion) bool {
+	for _, a := range assertions {
+		if a.Type == model.AssertStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func needsBody(assertions []model.Assertion) bool {
+	for _, a := range assertions {
+		switch a.Type {
+		case model.AssertBodyContains, model.AssertBodyNotContains, model.AssertBodyMatches, model.AssertJSONPathEquals:
+			return true
+		}
+	}
+	return false
+}
+
+func evaluateAssertions(assertions []model.Assertion, resp *http.Response, body []byte, elapsed time.Duration) []string {
+	var failures []string
+	for _, a := range assertions {
+		if failure := evaluateAssertion(a, resp, body, elapsed); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+func evaluateAssertion(a model.Assertion, resp *http.Response, body []byte, elapsed time.Duration) string {
+	switch a.Type {
+	case model.AssertStatusCode:
+		if !slices.Contains(a.StatusCodes, resp.StatusCode) {
+			return fmt.Sprintf("status code %d is not one of %v", resp.StatusCode, a.StatusCodes)
+		}
+	case model.AssertBodyContains:
+		if !bytes.Contains(body, []byte(a.Value)) {
+			return fmt.Sprintf("body does not contain %q", a.Value)
+		}
+	case model.AssertBodyNotContains:
+		if bytes.Contains(body, []byte(a.Value)) {
+			return fmt.Sprintf("body contains %q", a.Value)
+		}
+	case model.AssertBodyMatches:
+		if !regexp.MustCompile(a.Value).Match(body) {
+			return fmt.Sprintf("body does not match /%s/", a.Value)
+		}
+	case model.AssertJSONPathEquals:
+		return evaluateJSONPath(a, body)
+	case model.AssertHeaderPresent:
+		if len(resp.Header.Values(a.Header)) == 0 {
+			return fmt.Sprintf("header %s is missing", a.Header)
+		}
+	case model.AssertHeaderEquals:
+		values := resp.Header.Values(a.Header)
+		if len(values) == 0 {
+			return fmt.Sprintf("header %s is missing, expected %q", a.Header, a.Value)
+		}
+		if !slices.Contains(values, a.Value) {
+			return fmt.Sprintf("header %s is %q, expected %q", a.Header, strings.Join(values, ", "), a.Value)
+		}
+	case model.AssertMaxResponseTime:
+		if elapsed > a.MaxResponseTime {
+			return fmt.Sprintf("response time %s exceeds %s", elapsed.Round(time.Millisecond), a.MaxResponseTime)
+		}
+	}
+	return ""
+}
+
+func evaluateJSONPath(a model.Assertion, body []byte) string {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Sprintf("body is not valid JSON, expected %s to equal %s", a.JSONPath, formatJSON(a.JSONValue))
+	}
+
+	path, _ := parseJSONPath(a.JSONPath)
+	actual, ok := lookupJSONPath(doc, path)
+	if !ok {
+		return fmt.Sprintf("json path %s not found, expected %s", a.JSONPath, formatJSON(a.JSONValue))
+	}
+	if !reflect.DeepEqual(actual, a.JSONValue) {
+		return fmt.Sprintf("json path %s is %s, expected %s", a.JSONPath, formatJSON(actual), formatJSON(a.JSONValue))
+	}
+	return ""
+}
+
+func parseJSONPath(path string) ([]any, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if trimmed == "" {
+		if path == "" {
+			return nil, errors.New("json_path_equals requires a path")
+		}
+		return nil, nil
+	}
+
+	var tokens []any
+	for _, segment := range strings.Split(trimmed, ".") {
+		m := jsonPathSegment.FindStringSubmatch(segment)
+		if m == nil || (m[1] == "" && m[2] == "") {
+			return nil, fmt.Errorf("invalid json path %q", path)
+		}
+		if m[1] != "" {
+			tokens = append(tokens, m[1])
+		}
+		for _, index := range strings.Split(strings.Trim(m[2], "[]"), "][") {
+			if index == "" {
+				continue
+			}
+			n, _ := strconv.Atoi(index)
+			tokens = append(tokens, n)
+		}
+	}
+	return tokens, nil
+}
+
+func lookupJSONPath(doc any, path []any) (any, bool) {
+	current := doc
+	for _, token := range path {
+		switch key := token.(type) {
+		case string:
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[key]; !ok {
+				return nil, false
+			}
+		case int:
+			array, ok := current.([]any)
+			if !ok || key >= len(array) {
+				return nil, false
+			}
+			current = array[key]
+		}
+	}
+	return current, true
+}
+
+func formatJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
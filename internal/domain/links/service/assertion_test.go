@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func newAssertionServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","checks":[{"name":"db","up":true}],"version":2}`)
+	})
+	mux.HandleFunc("/error-page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<h1>Something went wrong</h1>`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLinkService_CheckLinks_AssertionsPass(t *testing.T) {
+	server := newAssertionServer(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	url := server.URL + "/health"
+	assertions := map[string][]model.Assertion{
+		url: {
+			{Type: model.AssertStatusCode, StatusCodes: []int{200}},
+			{Type: model.AssertBodyContains, Value: `"ok"`},
+			{Type: model.AssertBodyNotContains, Value: "error"},
+			{Type: model.AssertBodyMatches, Value: `"version":\d+`},
+			{Type: model.AssertJSONPathEquals, JSONPath: "$.status", JSONValue: "ok"},
+			{Type: model.AssertJSONPathEquals, JSONPath: "checks[0].up", JSONValue: true},
+			{Type: model.AssertJSONPathEquals, JSONPath: "$.version", JSONValue: float64(2)},
+			{Type: model.AssertHeaderPresent, Header: "Content-Type"},
+			{Type: model.AssertHeaderEquals, Header: "content-type", Value: "application/json"},
+			{Type: model.AssertMaxResponseTime, MaxResponseTime: time.Minute},
+		},
+	}
+
+	batch, err := service.CheckLinks(context.Background(), []string{url}, WithAssertions(assertions))
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusAvailable {
+		t.Errorf("Expected available, got %s with failures %v", batch.Links[0].Status, batch.Links[0].FailedAssertions)
+	}
+}
+
+func TestLinkService_CheckLinks_AssertionsFail(t *testing.T) {
+	server := newAssertionServer(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	errorPage := server.URL + "/error-page"
+	missing := server.URL + "/missing"
+	assertions := map[string][]model.Assertion{
+		errorPage: {
+			{Type: model.AssertBodyNotContains, Value: "went wrong"},
+			{Type: model.AssertJSONPathEquals, JSONPath: "$.status", JSONValue: "ok"},
+			{Type: model.AssertHeaderEquals, Header: "Content-Type", Value: "application/json"},
+		},
+		missing: {
+			{Type: model.AssertStatusCode, StatusCodes: []int{404, 410}},
+		},
+	}
+
+	batch, err := service.CheckLinks(context.Background(), []string{errorPage, missing}, WithAssertions(assertions))
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	failed := batch.Links[0]
+	if failed.Status != model.StatusAssertFailed {
+		t.Errorf("Expected assertion failed, got %s", failed.Status)
+	}
+	if len(failed.FailedAssertions) != 3 {
+		t.Fatalf("Expected 3 failed assertions, got %v", failed.FailedAssertions)
+	}
+	if !strings.Contains(failed.FailedAssertions[0], `body contains "went wrong"`) {
+		t.Errorf("Unexpected failure explanation: %s", failed.FailedAssertions[0])
+	}
+
+	if batch.Links[1].Status != model.StatusAvailable {
+		t.Errorf("Expected 404 to satisfy status assertion, got %s", batch.Links[1].Status)
+	}
+}
+
+func TestLinkService_CheckLinks_InvalidAssertion(t *testing.T) {
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	tests := []model.Assertion{
+		{Type: "unknown"},
+		{Type: model.AssertBodyMatches, Value: "("},
+		{Type: model.AssertJSONPathEquals, JSONPath: "a..b"},
+		{Type: model.AssertStatusCode},
+	}
+
+	for _, a := range tests {
+		_, err := service.CheckLinks(context.Background(), []string{"example.com"},
+			WithAssertions(map[string][]model.Assertion{"example.com": {a}}))
+		if !errors.Is(err, ErrInvalidAssertion) {
+			t.Errorf("Expected ErrInvalidAssertion for %+v, got %v", a, err)
+		}
+	}
+}
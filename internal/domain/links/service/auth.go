@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/normalize"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+	"golang.org/x/net/http/httpguts"
+)
+
+const redacted = "xxxxx"
+
+var urlPassword = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+
+// Credential is only sent to hosts matching Hosts (example.com or
+// *.example.com) and only usable by callers of Tenant.
+type Credential struct {
+	Username string
+	Password string
+	Token    string
+	Headers  http.Header
+	Hosts    []string
+	Tenant   string
+}
+
+func (c Credential) usableBy(tenantID string) bool {
+	owner := c.Tenant
+	if owner == "" {
+		owner = tenant.Default
+	}
+	return owner == tenantID
+}
+
+// covers reports whether every host matched by pattern is allowed.
+func (c Credential) covers(pattern string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	suffix, wildcard := strings.CutPrefix(pattern, "*.")
+	if !wildcard {
+		return matchesHostPattern(c.Hosts, pattern)
+	}
+	for _, allowed := range c.Hosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowedSuffix, ok := strings.CutPrefix(allowed, "*."); ok &&
+			(suffix == allowedSuffix || strings.HasSuffix(suffix, "."+allowedSuffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Credential) apply(header http.Header) {
+	for name, values := range c.Headers {
+		header[http.CanonicalHeaderKey(name)] = values
+	}
+	switch {
+	case c.Token != "":
+		header.Set("Authorization", "Bearer "+c.Token)
+	case c.Username != "" || c.Password != "":
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password)))
+	}
+}
+
+func (c Credential) secrets() []string {
+	var secrets []string
+	for _, secret := range []string{c.Password, c.Token} {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	for _, values := range c.Headers {
+		for _, value := range values {
+			if value != "" {
+				secrets = append(secrets, value)
+			}
+		}
+	}
+	return secrets
+}
+
+type requestAuth struct {
+	host   string
+	secure bool
+	header http.Header
+}
+
+type authContextKey struct{}
+
+func withRequestAuth(ctx context.Context, auth *requestAuth) context.Context {
+	if auth == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, authContextKey{}, auth)
+}
+
+// requestAuthFor returns the headers to add to req. Redirects and follow-up
+// requests to other hosts never carry them, nor do redirects from https to
+// plain http.
+func requestAuthFor(req *http.Request) *requestAuth {
+	auth, _ := req.Context().Value(authContextKey{}).(*requestAuth)
+	if auth == nil || !strings.EqualFold(req.URL.Hostname(), auth.host) {
+		return nil
+	}
+	if auth.secure && req.URL.Scheme != "https" {
+		return nil
+	}
+	return auth
+}
+
+func (s *linkService) validateAuth(ctx context.Context, opts checkOptions) error {
+	tenantID := tenant.FromContext(ctx)
+	check := func(where string, auth model.Auth, hosts []string) error {
+		if auth.Credential != "" {
+			// Credentials of other tenants are reported as unknown so their
+			// names do not leak.
+			credential, ok := s.credentials[auth.Credential]
+			if !ok || !credential.usableBy(tenantID) {
+				return fmt.Errorf("%w for %s: unknown credential %q", ErrInvalidAuth, where, auth.Credential)
+			}
+			for _, host := range hosts {
+				if !credential.covers(host) {
+					return fmt.Errorf("%w for %s: credential %q is not allowed for host %q", ErrInvalidAuth, where, auth.Credential, host)
+				}
+			}
+		}
+		for name, value := range auth.Headers {
+			if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+				return fmt.Errorf("%w for %s: invalid header %q", ErrInvalidAuth, where, name)
+			}
+		}
+		return nil
+	}
+
+	for link, auth := range opts.auth {
+		var hosts []string
+		if auth.Credential != "" {
+			u, err := normalize.Normalize(link, s.normalizeOptions)
+			if err != nil {
+				return fmt.Errorf("%w for %s: %v", ErrInvalidAuth, link, err)
+			}
+			hosts = []string{u.Hostname()}
+		}
+		if err := check(link, auth, hosts); err != nil {
+			return err
+		}
+	}
+	for i, rule := range opts.hostAuth {
+		where := fmt.Sprintf("host rule %d", i)
+		if len(rule.Hosts) == 0 {
+			return fmt.Errorf("%w for %s: no hosts", ErrInvalidAuth, where)
+		}
+		if err := check(where, rule.Auth, rule.Hosts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *linkService) normalizeAuthKeys(auth map[string]model.Auth) map[string]model.Auth {
+	if len(auth) == 0 {
+		return auth
+	}
+
+	normalized := make(map[string]model.Auth, len(auth))
+	for link, a := range auth {
+		if u, err := normalize.Normalize(link, s.normalizeOptions); err == nil {
+			link = checkKey(u)
+		}
+		normalized[link] = a
+	}
+	return normalized
+}
+
+// resolveAuth merges the first matching host rule with the per-link entry;
+// the link entry wins on conflicts.
+func (s *linkService) resolveAuth(opts checkOptions, key string, u *normalize.URL) *requestAuth {
+	host := strings.ToLower(u.Hostname())
+
+	var auths []model.Auth
+	for _, rule := range opts.hostAuth {
+		if matchesHostPattern(rule.Hosts, host) {
+			auths = append(auths, rule.Auth)
+			break
+		}
+	}
+	if auth, ok := opts.auth[key]; ok {
+		auths = append(auths, auth)
+	}
+	if len(auths) == 0 {
+		return nil
+	}
+
+	header := make(http.Header)
+	credential := ""
+	for _, auth := range auths {
+		for name, value := range auth.Headers {
+			header.Set(name, value)
+		}
+		if auth.Credential != "" {
+			credential = auth.Credential
+		}
+	}
+	// validateAuth already checked the hosts; this guards against rules
+	// added without it.
+	if c, ok := s.credentials[credential]; ok && c.covers(host) {
+		c.apply(header)
+	}
+	return &requestAuth{host: host, secure: u.Scheme == "https", header: header}
+}
+
+func matchesHostPattern(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func newRedactor(credentials map[string]Credential) *strings.Replacer {
+	var secrets []string
+	for _, credential := range credentials {
+		secrets = append(secrets, credential.secrets()...)
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	// Longer secrets first so a secret containing another is fully hidden.
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		pairs = append(pairs, secret, redacted)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func (s *linkService) redact(text string) string {
+	text = urlPassword.ReplaceAllString(text, "${1}"+redacted+"@")
+	if s.redactor != nil {
+		text = s.redactor.Replace(text)
+	}
+	return text
+}
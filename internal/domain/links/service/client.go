@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/proxy"
+)
+
+const maxRedirects = 10
+
+type clientSettings struct {
+	timeout               time.Duration
+	connectTimeout        time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	maxIdleConnsPerHost   int
+	keepAlive             time.Duration
+	http2                 bool
+	userAgent             string
+	headers               http.Header
+	tlsMinVersion         uint16
+	rootCAs               *x509.CertPool
+	insecureSkipVerify    bool
+	proxy                 *proxy.Selector
+}
+
+func defaultClientSettings() clientSettings {
+	return clientSettings{
+		timeout:             defaultTimeout,
+		connectTimeout:      30 * time.Second,
+		tlsHandshakeTimeout: 10 * time.Second,
+		maxIdleConnsPerHost: http.DefaultMaxIdleConnsPerHost,
+		keepAlive:           30 * time.Second,
+		http2:               true,
+	}
+}
+
+func (s *linkService) newHTTPClient() *http.Client {
+	settings := s.clientSettings
+
+	dialer := &net.Dialer{
+		Timeout:   settings.connectTimeout,
+		KeepAlive: settings.keepAlive,
+	}
+	if settings.keepAlive <= 0 {
+		dialer.KeepAlive = -1
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	transport.TLSHandshakeTimeout = settings.tlsHandshakeTimeout
+	transport.ResponseHeaderTimeout = settings.responseHeaderTimeout
+	transport.MaxIdleConnsPerHost = settings.maxIdleConnsPerHost
+	transport.DisableKeepAlives = settings.keepAlive <= 0
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         settings.tlsMinVersion,
+		RootCAs:            settings.rootCAs,
+		InsecureSkipVerify: settings.insecureSkipVerify,
+	}
+	if settings.http2 {
+		transport.ForceAttemptHTTP2 = true
+	} else {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	if settings.proxy != nil {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			proxyURL := settings.proxy.ProxyFor(req.URL)
+			if proxyURL == nil {
+				return nil, nil
+			}
+			if err := s.policy.CheckProxied(req.Context(), req.URL); err != nil {
+				return nil, err
+			}
+			return proxyURL, nil
+		}
+	}
+
+	client := &http.Client{
+		Timeout: settings.timeout,
+		Transport: &headerTransport{
+			base:      transport,
+			userAgent: settings.userAgent,
+			headers:   settings.headers,
+		},
+	}
+	if s.policy == nil {
+		return client
+	}
+
+	policyDial := s.policy.DialContext(dialer)
+	proxies := make(map[string]bool)
+	for _, address := range settings.proxy.Addresses() {
+		proxies[address] = true
+	}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		// Proxies come from trusted configuration and may live on internal
+		// networks; the targets behind them are vetted in transport.Proxy.
+		if proxies[address] {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return policyDial(ctx, network, address)
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("stopped after 10 redirects")
+		}
+		return s.policy.CheckURL(req.URL)
+	}
+	return client
+}
+
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	auth := requestAuthFor(req)
+	if t.userAgent == "" && len(t.headers) == 0 && auth == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	for name, values := range t.headers {
+		if req.Header.Get(name) == "" {
+			req.Header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	if auth != nil {
+		for name, values := range auth.header {
+			req.Header[name] = values
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/proxy"
+)
+
+func checkOne(t *testing.T, svc LinkService, url string) model.LinkCheck {
+	t.Helper()
+	batch, err := svc.CheckLinks(context.Background(), []string{url})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+	return batch.Links[0]
+}
+
+func TestLinkService_UserAgentAndDefaultHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	headers := http.Header{}
+	headers.Set("Accept-Language", "en")
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithUserAgent("link-checker-test/1.0"),
+		WithDefaultHeaders(headers),
+	)
+
+	if link := checkOne(t, svc, server.URL); link.Status != model.StatusAvailable {
+		t.Fatalf("Expected available, got %s", link.Status)
+	}
+
+	if ua := got.Get("User-Agent"); ua != "link-checker-test/1.0" {
+		t.Errorf("Expected custom User-Agent, got %q", ua)
+	}
+	if lang := got.Get("Accept-Language"); lang != "en" {
+		t.Errorf("Expected default header, got %q", lang)
+	}
+}
+
+func TestLinkService_CustomCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if link := checkOne(t, NewLinkService(repository.NewInMemoryLinkRepository()), server.URL); link.Status == model.StatusAvailable {
+		t.Fatal("Expected untrusted certificate to fail without a CA bundle")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithRootCAs(pool))
+
+	if link := checkOne(t, svc, server.URL); link.Status != model.StatusAvailable {
+		t.Errorf("Expected certificate to be trusted via CA bundle, got %s (%s)", link.Status, link.Error)
+	}
+}
+
+func TestLinkService_InsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithInsecureSkipVerify(true))
+
+	if link := checkOne(t, svc, server.URL); link.Status != model.StatusAvailable {
+		t.Errorf("Expected verification to be skipped, got %s (%s)", link.Status, link.Error)
+	}
+}
+
+func TestLinkService_TLSMinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithRootCAs(pool), WithTLSMinVersion(tls.VersionTLS13))
+
+	if link := checkOne(t, svc, server.URL); link.Status != model.StatusNotAvailable {
+		t.Errorf("Expected TLS 1.2 server to be rejected, got %s", link.Status)
+	}
+}
+
+func TestLinkService_HTTP2Toggle(t *testing.T) {
+	var proto int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.ProtoMajor
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	for _, enabled := range []bool{true, false} {
+		svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithRootCAs(pool), WithHTTP2(enabled))
+
+		if link := checkOne(t, svc, server.URL); link.Status != model.StatusAvailable {
+			t.Fatalf("Expected available, got %s (%s)", link.Status, link.Error)
+		}
+
+		want := 1
+		if enabled {
+			want = 2
+		}
+		if proto != want {
+			t.Errorf("HTTP/2 enabled=%v: expected HTTP/%d, got HTTP/%d", enabled, want, proto)
+		}
+	}
+}
+
+func TestLinkService_ResponseHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithResponseHeaderTimeout(20*time.Millisecond))
+
+	if link := checkOne(t, svc, server.URL); link.Status != model.StatusNotAvailable || link.Error == "" {
+		t.Errorf("Expected response header timeout, got %s %q", link.Status, link.Error)
+	}
+}
+
+type forwardProxy struct {
+	mu       sync.Mutex
+	requests []string
+	auth     []string
+}
+
+func (p *forwardProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.requests = append(p.requests, r.Method+" "+r.Host)
+	p.auth = append(p.auth, r.Header.Get("Proxy-Authorization"))
+	p.mu.Unlock()
+
+	if r.Method != http.MethodConnect {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	upstream, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	go pipe(upstream, buf)
+	go pipe(conn, upstream)
+}
+
+func pipe(dst io.WriteCloser, src io.Reader) {
+	io.Copy(dst, src)
+	dst.Close()
+}
+
+func newProxySelector(t *testing.T, raw string) *proxy.Selector {
+	t.Helper()
+	u, err := proxy.Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", raw, err)
+	}
+	return &proxy.Selector{HTTP: u, HTTPS: u}
+}
+
+func TestLinkService_HTTPProxy(t *testing.T) {
+	forward := &forwardProxy{}
+	proxyServer := httptest.NewServer(forward)
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	proxyURL.User = url.UserPassword("user", "secret")
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithProxy(newProxySelector(t, proxyURL.String())),
+	)
+
+	link := checkOne(t, svc, "http://example.test/")
+	if link.Status != model.StatusAvailable {
+		t.Fatalf("Expected available through proxy, got %s (%s)", link.Status, link.Error)
+	}
+	if link.Proxy != proxyServer.URL {
+		t.Errorf("Expected proxy %q in result, got %q", proxyServer.URL, link.Proxy)
+	}
+
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	if len(forward.auth) == 0 || forward.auth[0] != expected {
+		t.Errorf("Expected proxy credentials to be sent, got %v", forward.auth)
+	}
+}
+
+func TestLinkService_HTTPSProxyTunnel(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+
+	forward := &forwardProxy{}
+	proxyServer := httptest.NewServer(forward)
+	defer proxyServer.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(target.Certificate())
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithRootCAs(pool),
+		WithProxy(newProxySelector(t, proxyServer.URL)),
+	)
+
+	if link := checkOne(t, svc, target.URL); link.Status != model.StatusAvailable {
+		t.Fatalf("Expected available through CONNECT tunnel, got %s (%s)", link.Status, link.Error)
+	}
+	if len(forward.requests) == 0 || forward.requests[0] != "CONNECT "+target.Listener.Addr().String() {
+		t.Errorf("Expected CONNECT to target, got %v", forward.requests)
+	}
+}
+
+func TestLinkService_NoProxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+
+	forward := &forwardProxy{}
+	proxyServer := httptest.NewServer(forward)
+	defer proxyServer.Close()
+
+	selector := newProxySelector(t, proxyServer.URL)
+	selector.NoProxy = []string{"127.0.0.1"}
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithProxy(selector))
+
+	link := checkOne(t, svc, target.URL)
+	if link.Status != model.StatusAvailable || link.Proxy != "" {
+		t.Errorf("Expected direct connection, got %s via %q", link.Status, link.Proxy)
+	}
+	if len(forward.requests) != 0 {
+		t.Errorf("Expected proxy to be bypassed, got %v", forward.requests)
+	}
+}
+
+func serveSOCKS5(t *testing.T, username, password string) (string, *int32) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var connects int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				if upstream := socks5Handshake(conn, username, password); upstream != nil {
+					atomic.AddInt32(&connects, 1)
+					go pipe(upstream, conn)
+					pipe(conn, upstream)
+					return
+				}
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), &connects
+}
+
+func socks5Handshake(conn net.Conn, username, password string) net.Conn {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil || header[0] != 5 {
+		return nil
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return nil
+	}
+
+	if username == "" {
+		conn.Write([]byte{5, 0})
+	} else {
+		conn.Write([]byte{5, 2})
+		credentials := make([]byte, 2)
+		if _, err := io.ReadFull(conn, credentials); err != nil {
+			return nil
+		}
+		user := make([]byte, credentials[1])
+		io.ReadFull(conn, user)
+		length := make([]byte, 1)
+		io.ReadFull(conn, length)
+		pass := make([]byte, length[0])
+		io.ReadFull(conn, pass)
+		if string(user) != username || string(pass) != password {
+			conn.Write([]byte{1, 1})
+			return nil
+		}
+		conn.Write([]byte{1, 0})
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil || request[1] != 1 {
+		return nil
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, 4)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		io.ReadFull(conn, length)
+		name := make([]byte, length[0])
+		io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		return nil
+	}
+	port := make([]byte, 2)
+	io.ReadFull(conn, port)
+
+	upstream, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port[0])<<8|int(port[1]))))
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return nil
+	}
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	return upstream
+}
+
+func TestLinkService_SOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+
+	address, connects := serveSOCKS5(t, "user", "secret")
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithProxy(newProxySelector(t, "socks5://user:secret@"+address)),
+	)
+	link := checkOne(t, svc, target.URL)
+	if link.Status != model.StatusAvailable {
+		t.Fatalf("Expected available through SOCKS5, got %s (%s)", link.Status, link.Error)
+	}
+	if link.Proxy != "socks5://"+address {
+		t.Errorf("Expected SOCKS5 proxy in result without credentials, got %q", link.Proxy)
+	}
+	if atomic.LoadInt32(connects) == 0 {
+		t.Error("Expected the request to go through the SOCKS5 proxy")
+	}
+
+	svc = NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithProxy(newProxySelector(t, "socks5://user:wrong@"+address)),
+	)
+	if link := checkOne(t, svc, target.URL); link.Status == model.StatusAvailable {
+		t.Error("Expected wrong SOCKS5 credentials to fail")
+	}
+}
+
+func TestLinkService_ProxyAndDestinationPolicy(t *testing.T) {
+	proxyServer := httptest.NewServer(&forwardProxy{})
+	defer proxyServer.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithDestinationPolicy(&netpolicy.Policy{}),
+		WithProxy(newProxySelector(t, proxyServer.URL)),
+	)
+
+	if link := checkOne(t, svc, "http://example.test/"); link.Status != model.StatusAvailable {
+		t.Errorf("Expected loopback proxy to be exempt from the policy, got %s (%s)", link.Status, link.Error)
+	}
+	if link := checkOne(t, svc, "http://localhost:8080/"); link.Status != model.StatusBlocked {
+		t.Errorf("Expected internal target to stay blocked behind the proxy, got %s", link.Status)
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/extractor"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
+)
+
+const (
+	defaultCrawlMaxPages = 100
+	defaultCrawlMaxDepth = 5
+	defaultCrawlMaxLinks = 1000
+	maxCrawlPageSize     = 5 << 20
+)
+
+// crawlLimits are server-side ceilings for caller supplied crawl options.
+type crawlLimits struct {
+	maxPages int
+	maxDepth int
+	maxLinks int
+}
+
+func defaultCrawlLimits() crawlLimits {
+	return crawlLimits{
+		maxPages: defaultCrawlMaxPages,
+		maxDepth: defaultCrawlMaxDepth,
+		maxLinks: defaultCrawlMaxLinks,
+	}
+}
+
+func (l crawlLimits) clamp(opts model.CrawlOptions) model.CrawlOptions {
+	if opts.MaxPages <= 0 || opts.MaxPages > l.maxPages {
+		opts.MaxPages = l.maxPages
+	}
+	if opts.MaxDepth <= 0 || opts.MaxDepth > l.maxDepth {
+		opts.MaxDepth = l.maxDepth
+	}
+	if opts.MaxLinks <= 0 || opts.MaxLinks > l.maxLinks {
+		opts.MaxLinks = l.maxLinks
+	}
+	return opts
+}
+
+type crawlPage struct {
+	url   string
+	depth int
+}
+
+func (s *linkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	start, err := parseStartURL(opts.StartURL)
+	if err != nil {
+		return nil, err
+	}
+	opts = s.crawlLimits.clamp(opts)
+
+	inScope := func(link string) bool {
+		u, err := url.Parse(link)
+		if err != nil {
+			return false
+		}
+		if opts.SameHost && !strings.EqualFold(u.Host, start.Host) {
+			return false
+		}
+		return strings.HasPrefix(u.Path, opts.PathPrefix)
+	}
+
+	var discovered []string
+	var truncated bool
+	sources := make(map[string][]string)
+	addLink := func(link, source string) bool {
+		if _, exists := sources[link]; !exists {
+			if len(discovered) >= opts.MaxLinks {
+				truncated = true
+				return false
+			}
+			discovered = append(discovered, link)
+			sources[link] = nil
+		}
+		if source != "" {
+			sources[link] = append(sources[link], source)
+		}
+		return true
+	}
+
+	startURL := start.String()
+	addLink(startURL, "")
+
+	fetched := make(map[string]model.LinkCheck)
+	queued := map[string]bool{startURL: true}
+	queue := []crawlPage{{url: startURL}}
+
+	var pages []string
+	for len(queue) > 0 && len(pages) < opts.MaxPages && ctx.Err() == nil {
+		page := queue[0]
+		queue = queue[1:]
+
+		check, links := s.fetchPage(ctx, page.url)
+		fetched[page.url] = check
+		pages = append(pages, page.url)
+
+		for _, link := range links {
+			if !addLink(link, page.url) {
+				continue
+			}
+			if page.depth < opts.MaxDepth && !queued[link] && inScope(link) {
+				queued[link] = true
+				queue = append(queue, crawlPage{url: link, depth: page.depth + 1})
+			}
+		}
+	}
+
+	log.Printf("Crawled %d pages from %s, discovered %d links", len(pages), startURL, len(discovered))
+	if truncated {
+		log.Printf("Crawl of %s stopped collecting links at %d", startURL, opts.MaxLinks)
+	}
+
+	checks := make([]model.LinkCheck, len(discovered))
+	for i, link := range discovered {
+		check, ok := fetched[link]
+		if !ok {
+			check = s.checkURL(ctx, link, nil, checkOptions{})
+		}
+		check.Sources = sources[link]
+		checks[i] = check
+	}
+
+	batch, err := s.saveNewBatch(ctx, checks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CrawlResult{
+		Batch:        batch,
+		PagesCrawled: pages,
+		Truncated:    truncated,
+	}, nil
+}
+
+func (s *linkService) fetchPage(ctx context.Context, pageURL string) (model.LinkCheck, []string) {
+	check := model.LinkCheck{
+		URL:       pageURL,
+		Status:    model.StatusNotAvailable,
+		CheckedAt: time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		log.Printf("Failed to create request for %s: %v", pageURL, err)
+		return check, nil
+	}
+
+	if err := s.policy.CheckURL(req.URL); err != nil {
+		log.Printf("Blocked page %s: %v", pageURL, err)
+		check.Status = model.StatusBlocked
+		check.Error = err.Error()
+		return check, nil
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to crawl page %s: %v", pageURL, err)
+		check.Error = err.Error()
+		if errors.Is(err, netpolicy.ErrBlocked) {
+			check.Status = model.StatusBlocked
+		}
+		return check, nil
+	}
+	defer resp.Body.Close()
+
+	log.Printf("Page %s returned status %d", pageURL, resp.StatusCode)
+	check.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return check, nil
+	}
+	check.Status = model.StatusAvailable
+	if !isHTML(resp.Header.Get("Content-Type")) {
+		return check, nil
+	}
+
+	links, err := extractor.ExtractHTMLLinks(io.LimitReader(resp.Body, maxCrawlPageSize), resp.Request.URL)
+	if err != nil {
+		log.Printf("Failed to parse page %s: %v", pageURL, err)
+	}
+	return check, links
+}
+
+func parseStartURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start URL %q: %v", ErrInvalidURL, raw, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: start URL %q has no host", ErrInvalidURL, raw)
+	}
+	u.Fragment = ""
+	return u, nil
+}
+
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func newTestSite(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/docs/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/docs/guide">Guide</a><a href="/blog/">Blog</a><img src="/img/logo.png">`)
+	})
+	mux.HandleFunc("/docs/guide", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<a href="missing">Missing</a><a href="/docs/deep">Deep</a>`)
+	})
+	mux.HandleFunc("/docs/deep", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/docs/deeper">Deeper</a>`)
+	})
+	mux.HandleFunc("/blog/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/blog/post">Post</a>`)
+	})
+	mux.HandleFunc("/img/logo.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLinkService_Crawl(t *testing.T) {
+	server := newTestSite(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	result, err := service.Crawl(context.Background(), model.CrawlOptions{
+		StartURL:   server.URL + "/docs/",
+		SameHost:   true,
+		PathPrefix: "/docs/",
+		MaxDepth:   1,
+	})
+	if err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	if len(result.PagesCrawled) != 2 {
+		t.Errorf("Expected 2 crawled pages, got %v", result.PagesCrawled)
+	}
+
+	statuses := make(map[string]model.LinkCheck)
+	for _, link := range result.Batch.Links {
+		statuses[link.URL] = link
+	}
+
+	if len(statuses) != 6 {
+		t.Errorf("Expected 6 discovered links, got %d", len(statuses))
+	}
+
+	missing, ok := statuses[server.URL+"/docs/missing"]
+	if !ok {
+		t.Fatal("Expected /docs/missing to be discovered")
+	}
+	if missing.Status != model.StatusNotAvailable {
+		t.Errorf("Expected /docs/missing to be not available, got %s", missing.Status)
+	}
+	if len(missing.Sources) != 1 || missing.Sources[0] != server.URL+"/docs/guide" {
+		t.Errorf("Expected /docs/missing to be referenced from /docs/guide, got %v", missing.Sources)
+	}
+
+	if _, ok := statuses[server.URL+"/blog/post"]; ok {
+		t.Error("Pages outside the path prefix should not be crawled")
+	}
+	if _, ok := statuses[server.URL+"/docs/deeper"]; ok {
+		t.Error("Pages beyond max depth should not be crawled")
+	}
+}
+
+func TestLinkService_Crawl_MaxPages(t *testing.T) {
+	server := newTestSite(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	result, err := service.Crawl(context.Background(), model.CrawlOptions{
+		StartURL: server.URL + "/docs/",
+		SameHost: true,
+		MaxDepth: 5,
+		MaxPages: 1,
+	})
+	if err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	if len(result.PagesCrawled) != 1 {
+		t.Errorf("Expected 1 crawled page, got %d", len(result.PagesCrawled))
+	}
+}
+
+func TestLinkService_Crawl_ServerLimits(t *testing.T) {
+	server := newTestSite(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository(), WithCrawlLimits(2, 1, 3))
+
+	result, err := service.Crawl(context.Background(), model.CrawlOptions{
+		StartURL: server.URL + "/docs/",
+		MaxDepth: 50,
+		MaxPages: 1000,
+	})
+	if err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	if len(result.PagesCrawled) != 2 {
+		t.Errorf("Expected max_pages to be clamped to 2, got %v", result.PagesCrawled)
+	}
+	if len(result.Batch.Links) != 3 || !result.Truncated {
+		t.Errorf("Expected 3 links and a truncated result, got %d links, truncated=%v", len(result.Batch.Links), result.Truncated)
+	}
+}
+
+func TestLinkService_Crawl_ZeroDepthUsesServerLimit(t *testing.T) {
+	server := newTestSite(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository(), WithCrawlLimits(100, 1, 100))
+
+	result, err := service.Crawl(context.Background(), model.CrawlOptions{StartURL: server.URL + "/docs/"})
+	if err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+	if len(result.PagesCrawled) < 2 {
+		t.Errorf("Expected pages linked from the start page to be crawled, got %v", result.PagesCrawled)
+	}
+}
+
+func TestLinkService_Crawl_InvalidStartURL(t *testing.T) {
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	if _, err := service.Crawl(context.Background(), model.CrawlOptions{StartURL: "http://"}); err == nil {
+		t.Error("Expected error for start URL without host")
+	}
+}
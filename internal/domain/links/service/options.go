@@ -0,0 +1,204 @@
+package service
+
+import (
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/proxy"
+)
+
+const (
+	defaultCertExpiryWarning = 30 * 24 * time.Hour
+	defaultTimeout           = 10 * time.Second
+)
+
+type Option func(*linkService)
+
+func WithCertExpiryWarning(threshold time.Duration) Option {
+	return func(s *linkService) {
+		s.certExpiryWarning = threshold
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *linkService) {
+		s.clientSettings.timeout = timeout
+	}
+}
+
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(s *linkService) {
+		s.clientSettings.connectTimeout = timeout
+	}
+}
+
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(s *linkService) {
+		s.clientSettings.tlsHandshakeTimeout = timeout
+	}
+}
+
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(s *linkService) {
+		s.clientSettings.responseHeaderTimeout = timeout
+	}
+}
+
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(s *linkService) {
+		s.clientSettings.maxIdleConnsPerHost = n
+	}
+}
+
+func WithKeepAlive(period time.Duration) Option {
+	return func(s *linkService) {
+		s.clientSettings.keepAlive = period
+	}
+}
+
+func WithHTTP2(enabled bool) Option {
+	return func(s *linkService) {
+		s.clientSettings.http2 = enabled
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(s *linkService) {
+		s.clientSettings.userAgent = userAgent
+	}
+}
+
+func WithDefaultHeaders(headers http.Header) Option {
+	return func(s *linkService) {
+		s.clientSettings.headers = headers.Clone()
+	}
+}
+
+func WithTLSMinVersion(version uint16) Option {
+	return func(s *linkService) {
+		s.clientSettings.tlsMinVersion = version
+	}
+}
+
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(s *linkService) {
+		s.clientSettings.rootCAs = pool
+	}
+}
+
+func WithProxy(selector *proxy.Selector) Option {
+	return func(s *linkService) {
+		s.clientSettings.proxy = selector
+	}
+}
+
+func WithCredentials(credentials map[string]Credential) Option {
+	return func(s *linkService) {
+		s.credentials = credentials
+	}
+}
+
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(s *linkService) {
+		s.clientSettings.insecureSkipVerify = skip
+	}
+}
+
+func WithDestinationPolicy(policy *netpolicy.Policy) Option {
+	return func(s *linkService) {
+		s.policy = policy
+	}
+}
+
+// WithCrawlLimits caps the pages, depth and discovered links of a crawl;
+// zero keeps the default.
+func WithCrawlLimits(maxPages, maxDepth, maxLinks int) Option {
+	return func(s *linkService) {
+		if maxPages > 0 {
+			s.crawlLimits.maxPages = maxPages
+		}
+		if maxDepth > 0 {
+			s.crawlLimits.maxDepth = maxDepth
+		}
+		if maxLinks > 0 {
+			s.crawlLimits.maxLinks = maxLinks
+		}
+	}
+}
+
+func WithTrackingParamsStripped() Option {
+	return func(s *linkService) {
+		s.normalizeOptions.StripTrackingParams = true
+	}
+}
+
+func WithSchemeStrategy(strategy SchemeStrategy) Option {
+	return func(s *linkService) {
+		s.schemeStrategy = strategy
+	}
+}
+
+type CheckOption func(*checkOptions)
+
+type checkOptions struct {
+	validateAnchors bool
+	detectSoft404   bool
+	auditSecurity   bool
+	assertions      map[string][]model.Assertion
+	auth            map[string]model.Auth
+	hostAuth        []model.HostAuth
+
+	soft404  *soft404Detector
+	security *securityAuditor
+}
+
+func WithAnchorValidation() CheckOption {
+	return func(o *checkOptions) {
+		o.validateAnchors = true
+	}
+}
+
+func WithSoft404Detection() CheckOption {
+	return func(o *checkOptions) {
+		o.detectSoft404 = true
+	}
+}
+
+func WithSecurityAudit() CheckOption {
+	return func(o *checkOptions) {
+		o.auditSecurity = true
+	}
+}
+
+func WithAssertions(assertions map[string][]model.Assertion) CheckOption {
+	return func(o *checkOptions) {
+		o.assertions = assertions
+	}
+}
+
+func WithLinkAuth(auth map[string]model.Auth) CheckOption {
+	return func(o *checkOptions) {
+		o.auth = auth
+	}
+}
+
+func WithHostAuth(rules []model.HostAuth) CheckOption {
+	return func(o *checkOptions) {
+		o.hostAuth = rules
+	}
+}
+
+func newCheckOptions(opts []CheckOption) checkOptions {
+	var o checkOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o checkOptions) validate() error {
+	return validateAssertions(o.assertions)
+}
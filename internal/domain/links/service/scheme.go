@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/normalize"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type SchemeStrategy string
+
+const (
+	SchemeHTTPSOnly     SchemeStrategy = "https-only"
+	SchemeHTTPSThenHTTP SchemeStrategy = "https-then-http"
+	SchemeHTTPOnly      SchemeStrategy = "http-only"
+	SchemeBoth          SchemeStrategy = "both"
+)
+
+func ParseSchemeStrategy(value string) (SchemeStrategy, error) {
+	switch strategy := SchemeStrategy(strings.ToLower(value)); strategy {
+	case SchemeHTTPSOnly, SchemeHTTPSThenHTTP, SchemeHTTPOnly, SchemeBoth:
+		return strategy, nil
+	case "":
+		return SchemeHTTPSThenHTTP, nil
+	default:
+		return "", fmt.Errorf("unknown scheme strategy %q", value)
+	}
+}
+
+func checkKey(u *normalize.URL) string {
+	if u.ImplicitScheme {
+		return strings.TrimPrefix(u.String(), u.Scheme+":")
+	}
+	return u.String()
+}
+
+func (s *linkService) checkNormalized(ctx context.Context, u *normalize.URL, assertions []model.Assertion, opts checkOptions) model.LinkCheck {
+	if !u.ImplicitScheme {
+		return s.checkURL(ctx, u.String(), assertions, opts)
+	}
+
+	withScheme := func(scheme string) string {
+		copied := *u.URL
+		copied.Scheme = scheme
+		return copied.String()
+	}
+	responded := func(check model.LinkCheck) bool {
+		return check.StatusCode != 0
+	}
+
+	switch s.schemeStrategy {
+	case SchemeHTTPOnly:
+		return s.checkURL(ctx, withScheme("http"), assertions, opts)
+	case SchemeHTTPSOnly:
+		return s.checkURL(ctx, withScheme("https"), assertions, opts)
+	case SchemeBoth:
+		secure := s.checkURL(ctx, withScheme("https"), assertions, opts)
+		plain := s.checkURL(ctx, withScheme("http"), assertions, opts)
+		if secure.Status == model.StatusAvailable || plain.Status != model.StatusAvailable {
+			return secure
+		}
+		plain.InsecureOnly = true
+		return plain
+	default:
+		secure := s.checkURL(ctx, withScheme("https"), assertions, opts)
+		if responded(secure) {
+			return secure
+		}
+		plain := s.checkURL(ctx, withScheme("http"), assertions, opts)
+		if !responded(plain) {
+			return secure
+		}
+		plain.InsecureOnly = plain.Status == model.StatusAvailable
+		return plain
+	}
+}
+
+func schemeOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func TestLinkService_CheckLinks_SchemeStrategy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name         string
+		strategy     SchemeStrategy
+		status       model.LinkStatus
+		scheme       string
+		insecureOnly bool
+	}{
+		{"https then http falls back", SchemeHTTPSThenHTTP, model.StatusAvailable, "http", true},
+		{"both prefers working scheme", SchemeBoth, model.StatusAvailable, "http", true},
+		{"https only", SchemeHTTPSOnly, model.StatusNotAvailable, "https", false},
+		{"http only", SchemeHTTPOnly, model.StatusAvailable, "http", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithSchemeStrategy(tt.strategy))
+
+			batch, err := svc.CheckLinks(context.Background(), []string{host})
+			if err != nil {
+				t.Fatalf("CheckLinks failed: %v", err)
+			}
+
+			link := batch.Links[0]
+			if link.Status != tt.status || link.Scheme != tt.scheme || link.InsecureOnly != tt.insecureOnly {
+				t.Errorf("Expected %s over %s (insecure only %v), got %s over %s (insecure only %v)",
+					tt.status, tt.scheme, tt.insecureOnly, link.Status, link.Scheme, link.InsecureOnly)
+			}
+			if link.URL != host || link.NormalizedURL != tt.scheme+"://"+host+"/" {
+				t.Errorf("Unexpected URLs: %q %q", link.URL, link.NormalizedURL)
+			}
+		})
+	}
+}
+
+func TestLinkService_CheckLinks_PrefersHTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository()).(*linkService)
+	svc.client = server.Client()
+
+	batch, err := svc.CheckLinks(context.Background(), []string{strings.TrimPrefix(server.URL, "https://")})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	link := batch.Links[0]
+	if link.Status != model.StatusAvailable || link.Scheme != "https" || link.InsecureOnly {
+		t.Errorf("Expected host to be checked over https, got %+v", link)
+	}
+}
+
+func TestLinkService_CheckLinks_ExplicitSchemeKept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(), WithSchemeStrategy(SchemeHTTPSOnly))
+
+	batch, err := svc.CheckLinks(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if link := batch.Links[0]; link.Status != model.StatusAvailable || link.Scheme != "http" {
+		t.Errorf("Expected explicit http URL to be checked as given, got %+v", link)
+	}
+}
+
+func TestParseSchemeStrategy(t *testing.T) {
+	if strategy, err := ParseSchemeStrategy(""); err != nil || strategy != SchemeHTTPSThenHTTP {
+		t.Errorf("Expected default strategy, got %q %v", strategy, err)
+	}
+	if strategy, err := ParseSchemeStrategy("BOTH"); err != nil || strategy != SchemeBoth {
+		t.Errorf("Expected both, got %q %v", strategy, err)
+	}
+	if _, err := ParseSchemeStrategy("ftp-first"); err == nil {
+		t.Error("Expected unknown strategy to be rejected")
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/extractor"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type securityCheck struct {
+	header string
+	weight int
+}
+
+var securityHeaders = []securityCheck{
+	{header: "Strict-Transport-Security", weight: 20},
+	{header: "Content-Security-Policy", weight: 20},
+	{header: "X-Frame-Options", weight: 10},
+	{header: "X-Content-Type-Options", weight: 10},
+	{header: "Referrer-Policy", weight: 10},
+}
+
+const (
+	httpsRedirectWeight = 20
+	mixedContentWeight  = 10
+)
+
+type securityAuditor struct {
+	s         *linkService
+	redirects map[string]bool
+}
+
+func newSecurityAuditor(s *linkService) *securityAuditor {
+	return &securityAuditor{
+		s:         s,
+		redirects: make(map[string]bool),
+	}
+}
+
+func (a *securityAuditor) audit(ctx context.Context, requested *url.URL, resp *http.Response, body []byte) *model.SecurityAudit {
+	audit := &model.SecurityAudit{
+		Headers: make(map[string]string),
+	}
+
+	for _, check := range securityHeaders {
+		value := resp.Header.Get(check.header)
+		if value == "" && check.header == "X-Frame-Options" && strings.Contains(resp.Header.Get("Content-Security-Policy"), "frame-ancestors") {
+			value = "(via Content-Security-Policy frame-ancestors)"
+		}
+		if value == "" {
+			audit.MissingHeaders = append(audit.MissingHeaders, check.header)
+			continue
+		}
+		audit.Headers[check.header] = value
+		audit.Score += check.weight
+	}
+
+	final := resp.Request.URL
+	switch {
+	case requested.Scheme == "http" && final.Scheme == "https":
+		audit.RedirectsToHTTPS = true
+	case final.Scheme == "https":
+		audit.RedirectsToHTTPS = a.redirectsToHTTPS(ctx, final)
+	}
+	if audit.RedirectsToHTTPS {
+		audit.Score += httpsRedirectWeight
+	}
+
+	if final.Scheme == "https" && isHTML(resp.Header.Get("Content-Type")) {
+		insecure, err := extractor.ExtractMixedContent(bytes.NewReader(body))
+		if err != nil {
+			log.Printf("Failed to parse %s for mixed content: %v", final, err)
+		}
+		audit.MixedContent = insecure
+	}
+	if len(audit.MixedContent) == 0 {
+		audit.Score += mixedContentWeight
+	}
+
+	return audit
+}
+
+func (a *securityAuditor) redirectsToHTTPS(ctx context.Context, secure *url.URL) bool {
+	if redirects, ok := a.redirects[secure.Host]; ok {
+		return redirects
+	}
+
+	plain := &url.URL{Scheme: "http", Host: secure.Host, Path: secure.Path, RawQuery: secure.RawQuery}
+	if port := secure.Port(); port == "" || port == "443" {
+		plain.Host = secure.Hostname()
+	}
+
+	redirects := false
+	defer func() { a.redirects[secure.Host] = redirects }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", plain.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := a.s.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to check https redirect for %s: %v", plain, err)
+		return false
+	}
+	resp.Body.Close()
+
+	redirects = resp.Request.URL.Scheme == "https"
+	return redirects
+}
+
+func summarizeSecurity(links []model.LinkCheck) *model.SecuritySummary {
+	summary := &model.SecuritySummary{
+		MissingHeaders: make(map[string]int),
+	}
+
+	total := 0
+	seen := make(map[string]bool)
+	for _, link := range links {
+		key := link.NormalizedURL
+		if key == "" {
+			key = link.URL
+		}
+		if link.Security == nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		summary.Audited++
+		total += link.Security.Score
+		for _, header := range link.Security.MissingHeaders {
+			summary.MissingHeaders[header]++
+		}
+		if !link.Security.RedirectsToHTTPS {
+			summary.WithoutHTTPSRedirect++
+		}
+		if len(link.Security.MixedContent) > 0 {
+			summary.WithMixedContent++
+		}
+	}
+
+	if summary.Audited == 0 {
+		return nil
+	}
+	summary.Score = total / summary.Audited
+	return summary
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+)
+
+func TestLinkService_CheckLinks_SecurityAudit(t *testing.T) {
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<img src="http://images.example.com/logo.png">`)
+	}))
+	defer secure.Close()
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, secure.URL+"/", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+	}))
+	defer plain.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository()).(*linkService)
+	svc.client = secure.Client()
+
+	batch, err := svc.CheckLinks(context.Background(), []string{plain.URL + "/redirect", plain.URL + "/"}, WithSecurityAudit())
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	redirected := batch.Links[0].Security
+	if redirected == nil {
+		t.Fatal("Expected security audit for redirected link")
+	}
+	if !redirected.RedirectsToHTTPS {
+		t.Error("Expected http to https redirect to be detected")
+	}
+	if redirected.Headers["X-Frame-Options"] == "" {
+		t.Error("Expected frame-ancestors to satisfy X-Frame-Options")
+	}
+	if len(redirected.MissingHeaders) != 1 || redirected.MissingHeaders[0] != "Referrer-Policy" {
+		t.Errorf("Expected only Referrer-Policy to be missing, got %v", redirected.MissingHeaders)
+	}
+	if len(redirected.MixedContent) != 1 {
+		t.Errorf("Expected mixed content to be reported, got %v", redirected.MixedContent)
+	}
+	if redirected.Score != 80 {
+		t.Errorf("Expected score 80, got %d", redirected.Score)
+	}
+
+	insecure := batch.Links[1].Security
+	if insecure.RedirectsToHTTPS || insecure.Score != mixedContentWeight {
+		t.Errorf("Expected plain http page to score %d, got %+v", mixedContentWeight, insecure)
+	}
+
+	summary := batch.Security
+	if summary == nil || summary.Audited != 2 || summary.Score != 45 {
+		t.Fatalf("Unexpected batch summary: %+v", summary)
+	}
+	if summary.MissingHeaders["Referrer-Policy"] != 2 || summary.WithoutHTTPSRedirect != 1 || summary.WithMixedContent != 1 {
+		t.Errorf("Unexpected batch summary: %+v", summary)
+	}
+
+	if _, err := svc.GenerateReport(context.Background(), []string{batch.ID}); err != nil {
+		t.Errorf("GenerateReport failed: %v", err)
+	}
+}
+
+func TestLinkService_CheckLinks_NoSecurityAuditByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	batch, err := service.CheckLinks(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Security != nil || batch.Security != nil {
+		t.Error("Expected no security audit without the option")
+	}
+}
@@ -3,50 +3,164 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/normalize"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/proxy"
 	"github.com/jung-kurt/gofpdf"
 )
 
+var (
+	ErrInvalidURL       = errors.New("invalid URL")
+	ErrSitemapNotFound  = errors.New("sitemap not found")
+	ErrInvalidAssertion = errors.New("invalid assertion")
+	ErrInvalidAuth      = errors.New("invalid auth")
+)
+
+const (
+	maxBodySize  = 5 << 20
+	batchIDBytes = 16
+)
+
 type LinkService interface {
-	CheckLinks(ctx context.Context, urls []string) (*model.LinkBatch, error)
-	GenerateReport(batchIDs []int) ([]byte, error)
+	CheckLinks(ctx context.Context, urls []string, opts ...CheckOption) (*model.LinkBatch, error)
+	Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error)
+	CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error)
+	GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error)
+	GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error)
 }
 
 type linkService struct {
 	repo   repository.LinkRepository
 	client *http.Client
+	policy *netpolicy.Policy
+
+	normalizeOptions normalize.Options
+	schemeStrategy   SchemeStrategy
+	clientSettings   clientSettings
+	credentials      map[string]Credential
+	redactor         *strings.Replacer
+	crawlLimits      crawlLimits
+
+	certExpiryWarning time.Duration
 }
 
-func NewLinkService(repo repository.LinkRepository) LinkService {
-	return &linkService{
-		repo: repo,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+func NewLinkService(repo repository.LinkRepository, opts ...Option) LinkService {
+	s := &linkService{
+		repo:              repo,
+		certExpiryWarning: defaultCertExpiryWarning,
+		schemeStrategy:    SchemeHTTPSThenHTTP,
+		clientSettings:    defaultClientSettings(),
+		crawlLimits:       defaultCrawlLimits(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.client = s.newHTTPClient()
+	s.redactor = newRedactor(s.credentials)
+	return s
 }
 
-func (s *linkService) CheckLinks(ctx context.Context, urls []string) (*model.LinkBatch, error) {
-	batch := &model.LinkBatch{
-		ID:        s.repo.GetNextID(),
-		Links:     make([]model.LinkCheck, len(urls)),
-		CreatedAt: time.Now(),
+func (s *linkService) CheckLinks(ctx context.Context, urls []string, opts ...CheckOption) (*model.LinkBatch, error) {
+	options := newCheckOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	if err := s.validateAuth(ctx, options); err != nil {
+		return nil, err
+	}
+	if options.detectSoft404 {
+		options.soft404 = newSoft404Detector(s)
 	}
+	if options.auditSecurity {
+		options.security = newSecurityAuditor(s)
+	}
+
+	options.assertions = s.normalizeAssertionKeys(options.assertions)
+	options.auth = s.normalizeAuthKeys(options.auth)
 
-	for i, url := range urls {
-		status := s.checkURL(ctx, url)
-		batch.Links[i] = model.LinkCheck{
-			URL:       url,
-			Status:    status,
-			CheckedAt: time.Now(),
+	checks := make([]model.LinkCheck, len(urls))
+	results := make(map[string]model.LinkCheck)
+	for i, raw := range urls {
+		normalized, err := normalize.Normalize(raw, s.normalizeOptions)
+		if err != nil {
+			log.Printf("Rejected URL %q: %v", s.redact(raw), err)
+			checks[i] = model.LinkCheck{
+				URL:       raw,
+				Status:    model.StatusInvalid,
+				Error:     err.Error(),
+				CheckedAt: time.Now(),
+			}
+			continue
 		}
+
+		key := checkKey(normalized)
+		checked, ok := results[key]
+		if !ok {
+			checkCtx := withRequestAuth(ctx, s.resolveAuth(options, key, normalized))
+			checked = s.checkNormalized(checkCtx, normalized, options.assertions[key], options)
+			results[key] = checked
+		}
+
+		result := checked
+		result.URL = raw
+		result.NormalizedURL = checked.URL
+		checks[i] = result
+	}
+
+	log.Printf("Checked %d unique URLs for %d inputs", len(results), len(urls))
+	return s.saveNewBatch(ctx, checks)
+}
+
+func (s *linkService) normalizeAssertionKeys(assertions map[string][]model.Assertion) map[string][]model.Assertion {
+	if len(assertions) == 0 {
+		return assertions
+	}
+
+	normalized := make(map[string][]model.Assertion, len(assertions))
+	for link, list := range assertions {
+		if u, err := normalize.Normalize(link, s.normalizeOptions); err == nil {
+			link = checkKey(u)
+		}
+		normalized[link] = append(normalized[link], list...)
+	}
+	return normalized
+}
+
+// newBatchID returns a random identifier, so that batches of other tenants
+// cannot be found by guessing.
+func newBatchID() (string, error) {
+	buf := make([]byte, batchIDBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *linkService) saveNewBatch(ctx context.Context, checks []model.LinkCheck) (*model.LinkBatch, error) {
+	id, err := newBatchID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate batch ID: %w", err)
+	}
+
+	batch := &model.LinkBatch{
+		ID:        id,
+		Tenant:    tenant.FromContext(ctx),
+		Links:     checks,
+		CreatedAt: time.Now(),
+		Security:  summarizeSecurity(checks),
 	}
 
 	if err := s.repo.SaveBatch(batch); err != nil {
@@ -56,39 +170,127 @@ func (s *linkService) CheckLinks(ctx context.Context, urls []string) (*model.Lin
 	return batch, nil
 }
 
-func (s *linkService) checkURL(ctx context.Context, url string) model.LinkStatus {
+func (s *linkService) checkURL(ctx context.Context, url string, assertions []model.Assertion, opts checkOptions) (check model.LinkCheck) {
 	originalURL := url
+	logURL := s.redact(originalURL)
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
 		url = "http://" + url
 	}
 
+	check = model.LinkCheck{
+		URL:    originalURL,
+		Status: model.StatusNotAvailable,
+		Scheme: schemeOf(url),
+	}
+	defer func() { check.CheckedAt = time.Now() }()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		log.Printf("Failed to create request for %s: %v", originalURL, err)
-		return model.StatusNotAvailable
+		log.Printf("Failed to create request for %s: %v", logURL, s.redact(err.Error()))
+		check.Error = s.redact(err.Error())
+		return check
 	}
 
+	if err := s.policy.CheckURL(req.URL); err != nil {
+		log.Printf("Blocked URL %s: %v", logURL, s.redact(err.Error()))
+		check.Status = model.StatusBlocked
+		check.Error = s.redact(err.Error())
+		return check
+	}
+
+	start := time.Now()
 	resp, err := s.client.Do(req)
+	check.Proxy = proxy.Display(s.clientSettings.proxy.ProxyFor(req.URL))
 	if err != nil {
-		log.Printf("Failed to check URL %s: %v", originalURL, err)
-		return model.StatusNotAvailable
+		log.Printf("Failed to check URL %s: %v", logURL, s.redact(err.Error()))
+		check.Error = s.redact(err.Error())
+		if errors.Is(err, netpolicy.ErrBlocked) {
+			check.Status = model.StatusBlocked
+			return check
+		}
+		check.TLS = inspectVerificationError(req.URL.Hostname(), err)
+		return check
 	}
 	defer resp.Body.Close()
+	check.ResponseTime = time.Since(start)
+	check.Proxy = proxy.Display(s.clientSettings.proxy.ProxyFor(resp.Request.URL))
+	if resp.TLS != nil {
+		check.TLS = inspectConnection(resp.Request.URL.Hostname(), resp.TLS)
+	}
+
+	log.Printf("URL %s returned status %d", logURL, resp.StatusCode)
+	check.StatusCode = resp.StatusCode
+
+	validateAnchor := opts.validateAnchors && shouldValidateFragment(req.URL.Fragment)
+	detectSoft404 := opts.soft404 != nil && resp.StatusCode >= 200 && resp.StatusCode < 300
+	var body []byte
+	if validateAnchor || detectSoft404 || opts.security != nil || needsBody(assertions) {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			log.Printf("Failed to read body of %s: %v", logURL, s.redact(err.Error()))
+		}
+	}
+
+	if opts.security != nil {
+		check.Security = opts.security.audit(ctx, req.URL, resp, body)
+	}
+
+	if !hasStatusAssertion(assertions) && (resp.StatusCode < 200 || resp.StatusCode >= 400) {
+		return check
+	}
+	check.Status = model.StatusAvailable
+
+	if validateAnchor {
+		found, err := hasAnchor(resp.Header.Get("Content-Type"), body, req.URL.Fragment)
+		if err != nil {
+			log.Printf("Failed to parse %s for anchors: %v", logURL, s.redact(err.Error()))
+		}
+		if !found {
+			log.Printf("URL %s has no anchor #%s", logURL, req.URL.Fragment)
+			check.Status = model.StatusBrokenAnchor
+		}
+	}
 
-	log.Printf("URL %s returned status %d", originalURL, resp.StatusCode)
-	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-		return model.StatusAvailable
+	if check.Status == model.StatusAvailable && check.TLS != nil &&
+		time.Duration(check.TLS.DaysUntilExpiry)*24*time.Hour < s.certExpiryWarning {
+		log.Printf("URL %s certificate expires in %d days", logURL, check.TLS.DaysUntilExpiry)
+		check.Status = model.StatusCertExpiring
 	}
 
-	return model.StatusNotAvailable
+	if detectSoft404 && check.Status == model.StatusAvailable {
+		check.Soft404Confidence = opts.soft404.confidence(ctx, req.URL, resp.Request.URL.String(), body)
+		if check.Soft404Confidence >= soft404Threshold {
+			log.Printf("URL %s looks like a soft 404 (confidence %.2f)", logURL, check.Soft404Confidence)
+			check.Status = model.StatusSoft404
+		}
+	}
+
+	if failures := evaluateAssertions(assertions, resp, body, check.ResponseTime); len(failures) > 0 {
+		log.Printf("URL %s failed %d assertions", logURL, len(failures))
+		check.Status = model.StatusAssertFailed
+		check.FailedAssertions = failures
+	}
+
+	return check
+}
+
+func (s *linkService) GenerateReport(ctx context.Context, batchIDs []string) ([]byte, error) {
+	batches, err := s.repo.GetBatches(tenant.FromContext(ctx), batchIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batches: %w", err)
+	}
+	return renderReport(batches)
 }
 
-func (s *linkService) GenerateReport(batchIDs []int) ([]byte, error) {
-	batches, err := s.repo.GetBatches(batchIDs)
+func (s *linkService) GenerateReportByNumbers(ctx context.Context, numbers []int) ([]byte, error) {
+	batches, err := s.repo.GetBatchesByNumber(tenant.FromContext(ctx), numbers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get batches: %w", err)
 	}
+	return renderReport(batches)
+}
 
+func renderReport(batches []*model.LinkBatch) ([]byte, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
 	pdf.SetFont("Arial", "B", 16)
@@ -97,18 +299,38 @@ func (s *linkService) GenerateReport(batchIDs []int) ([]byte, error) {
 
 	pdf.SetFont("Arial", "", 12)
 	for _, batch := range batches {
-		pdf.Cell(40, 10, fmt.Sprintf("Batch ID: %d", batch.ID))
+		pdf.Cell(40, 10, fmt.Sprintf("Batch ID: %s", batch.ID))
 		pdf.Ln(8)
 
 		for _, link := range batch.Links {
-			status := "Available"
-			if link.Status == model.StatusNotAvailable {
-				status = "Not Available"
+			status := reportStatus(link.Status)
+			if link.Status == model.StatusSoft404 {
+				status = fmt.Sprintf("%s (confidence %.0f%%)", status, link.Soft404Confidence*100)
 			}
 			pdf.Cell(40, 10, fmt.Sprintf("%s - %s", link.URL, status))
 			pdf.Ln(6)
+
+			if link.InsecureOnly {
+				pdf.Cell(40, 10, "    reachable over plain HTTP only")
+				pdf.Ln(6)
+			}
+
+			for _, failure := range link.FailedAssertions {
+				pdf.Cell(40, 10, fmt.Sprintf("    assertion failed: %s", failure))
+				pdf.Ln(6)
+			}
+
+			if link.Status != model.StatusAvailable {
+				for _, source := range link.Sources {
+					pdf.Cell(40, 10, fmt.Sprintf("    referenced from %s", source))
+					pdf.Ln(6)
+				}
+			}
 		}
 		pdf.Ln(4)
+
+		writeCertificateSection(pdf, batch)
+		writeSecuritySection(pdf, batch)
 	}
 
 	var buf bytes.Buffer
@@ -117,3 +339,94 @@ func (s *linkService) GenerateReport(batchIDs []int) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+func reportStatus(status model.LinkStatus) string {
+	words := strings.Fields(string(status))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func writeCertificateSection(pdf *gofpdf.Fpdf, batch *model.LinkBatch) {
+	var links []model.LinkCheck
+	for _, link := range batch.Links {
+		if link.TLS != nil && len(link.TLS.Chain) > 0 {
+			links = append(links, link)
+		}
+	}
+	if len(links) == 0 {
+		return
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "Certificates")
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 12)
+
+	for _, link := range links {
+		leaf := link.TLS.Chain[0]
+		pdf.Cell(40, 10, link.URL)
+		pdf.Ln(6)
+		pdf.Cell(40, 10, fmt.Sprintf("    subject: %s", leaf.Subject))
+		pdf.Ln(6)
+		pdf.Cell(40, 10, fmt.Sprintf("    issuer: %s", leaf.Issuer))
+		pdf.Ln(6)
+		pdf.Cell(40, 10, fmt.Sprintf("    expires: %s (%d days)", leaf.NotAfter.Format("2006-01-02"), link.TLS.DaysUntilExpiry))
+		pdf.Ln(6)
+
+		var problems []string
+		if link.TLS.HostnameMismatch {
+			problems = append(problems, "hostname mismatch")
+		}
+		if link.TLS.SelfSigned {
+			problems = append(problems, "self-signed")
+		}
+		if link.TLS.VerifyError != "" {
+			problems = append(problems, link.TLS.VerifyError)
+		}
+		if len(problems) > 0 {
+			pdf.Cell(40, 10, fmt.Sprintf("    problems: %s", strings.Join(problems, ", ")))
+			pdf.Ln(6)
+		}
+		if link.TLS.Version != "" {
+			pdf.Cell(40, 10, fmt.Sprintf("    %s, %s", link.TLS.Version, link.TLS.CipherSuite))
+			pdf.Ln(6)
+		}
+	}
+	pdf.Ln(4)
+}
+
+func writeSecuritySection(pdf *gofpdf.Fpdf, batch *model.LinkBatch) {
+	if batch.Security == nil {
+		return
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, fmt.Sprintf("Security audit - score %d/100 (%d links)", batch.Security.Score, batch.Security.Audited))
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 12)
+
+	for _, check := range securityHeaders {
+		if missing := batch.Security.MissingHeaders[check.header]; missing > 0 {
+			pdf.Cell(40, 10, fmt.Sprintf("    %s missing on %d links", check.header, missing))
+			pdf.Ln(6)
+		}
+	}
+	if batch.Security.WithoutHTTPSRedirect > 0 {
+		pdf.Cell(40, 10, fmt.Sprintf("    no http to https redirect on %d links", batch.Security.WithoutHTTPSRedirect))
+		pdf.Ln(6)
+	}
+	if batch.Security.WithMixedContent > 0 {
+		pdf.Cell(40, 10, fmt.Sprintf("    mixed content on %d pages", batch.Security.WithMixedContent))
+		pdf.Ln(6)
+	}
+
+	for _, link := range batch.Links {
+		if link.Security != nil {
+			pdf.Cell(40, 10, fmt.Sprintf("%s - %d/100", link.URL, link.Security.Score))
+			pdf.Ln(6)
+		}
+	}
+	pdf.Ln(4)
+}
@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
 )
 
 func TestLinkService_CheckLinks(t *testing.T) {
@@ -27,8 +32,33 @@ func TestLinkService_CheckLinks(t *testing.T) {
 		t.Errorf("Expected 2 links, got %d", len(batch.Links))
 	}
 
-	if batch.ID != 1 {
-		t.Errorf("Expected batch ID 1, got %d", batch.ID)
+	if batch.ID == "" || batch.Tenant != tenant.Default {
+		t.Errorf("Expected batch with ID in the default tenant, got %q in %q", batch.ID, batch.Tenant)
+	}
+}
+
+func TestLinkService_CheckLinks_Tenant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	repo := repository.NewInMemoryLinkRepository()
+	service := NewLinkService(repo)
+
+	ctx := tenant.WithTenant(context.Background(), "team-a")
+	first, err := service.CheckLinks(ctx, []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+	second, _ := service.CheckLinks(ctx, []string{server.URL})
+
+	if first.Tenant != "team-a" {
+		t.Errorf("Expected batch to belong to team-a, got %q", first.Tenant)
+	}
+	if first.ID == second.ID || len(first.ID) < 20 {
+		t.Errorf("Expected distinct random batch IDs, got %q and %q", first.ID, second.ID)
+	}
+	if batch, _ := repo.GetBatch("team-b", first.ID); batch != nil {
+		t.Error("Expected batch to be hidden from other tenants")
 	}
 }
 
@@ -37,14 +67,15 @@ func TestLinkService_GenerateReport(t *testing.T) {
 	service := NewLinkService(repo)
 
 	batch := &model.LinkBatch{
-		ID: 1,
+		ID:     "b1",
+		Tenant: tenant.Default,
 		Links: []model.LinkCheck{
 			{URL: "google.com", Status: model.StatusAvailable},
 		},
 	}
 	repo.SaveBatch(batch)
 
-	pdfData, err := service.GenerateReport([]int{1})
+	pdfData, err := service.GenerateReport(context.Background(), []string{"b1"})
 	if err != nil {
 		t.Fatalf("GenerateReport failed: %v", err)
 	}
@@ -52,4 +83,108 @@ func TestLinkService_GenerateReport(t *testing.T) {
 	if len(pdfData) == 0 {
 		t.Error("Expected PDF data, got empty slice")
 	}
-}
\ No newline at end of file
+}
+func TestLinkService_CheckLinks_DestinationPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithDestinationPolicy(&netpolicy.Policy{AllowedSchemes: []string{"http", "https"}}),
+	)
+
+	batch, err := service.CheckLinks(context.Background(), []string{server.URL, "localhost:1"})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	for _, link := range batch.Links {
+		if link.Status != model.StatusBlocked || link.Error == "" {
+			t.Errorf("Expected %s to be blocked with a reason, got %s %q", link.URL, link.Status, link.Error)
+		}
+	}
+}
+
+func TestLinkService_CheckLinks_DestinationPolicyRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	allow, _ := netpolicy.ParseCIDRs([]string{"127.0.0.0/8"})
+	service := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithDestinationPolicy(&netpolicy.Policy{AllowCIDRs: allow}),
+	)
+
+	batch, err := service.CheckLinks(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusBlocked {
+		t.Errorf("Expected redirect to metadata service to be blocked, got %s", batch.Links[0].Status)
+	}
+}
+
+func TestLinkService_CheckLinks_NormalizesAndDeduplicates(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	urls := []string{
+		server.URL + "/docs/",
+		"  " + server.URL + "/docs/./ ",
+		strings.Replace(server.URL, "http://", "HTTP://", 1) + "/a/../docs/",
+		"not a url",
+		"ftp://example.com/file",
+	}
+
+	batch, err := service.CheckLinks(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if len(batch.Links) != len(urls) {
+		t.Fatalf("Expected a result for every input, got %d", len(batch.Links))
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected duplicates to be checked once, got %d requests", requests)
+	}
+
+	for i, link := range batch.Links[:3] {
+		if link.URL != urls[i] {
+			t.Errorf("Expected result %d to keep original input %q, got %q", i, urls[i], link.URL)
+		}
+		if link.NormalizedURL != server.URL+"/docs/" || link.Status != model.StatusAvailable {
+			t.Errorf("Unexpected result for %q: %+v", urls[i], link)
+		}
+	}
+
+	for _, link := range batch.Links[3:] {
+		if link.Status != model.StatusInvalid || link.Error == "" {
+			t.Errorf("Expected %q to be rejected with a validation error, got %s %q", link.URL, link.Status, link.Error)
+		}
+	}
+}
+
+func TestLinkService_CheckLinks_StripsTrackingParams(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+	}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository(), WithTrackingParamsStripped())
+
+	if _, err := service.CheckLinks(context.Background(), []string{server.URL + "/?id=1&utm_source=mail"}); err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if query != "id=1" {
+		t.Errorf("Expected tracking parameters to be stripped, got %q", query)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/robots"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/sitemap"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+const (
+	maxSitemapFiles = 50
+	maxSitemapURLs  = 50000
+	maxSitemapSize  = 50 << 20
+	maxRobotsSize   = 512 << 10
+)
+
+// CheckSitemap checks the sitemap entries, at most maxLinks of them; zero
+// uses the service limit.
+func (s *linkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	if maxLinks <= 0 || maxLinks > maxSitemapURLs {
+		maxLinks = maxSitemapURLs
+	}
+
+	u, err := parseStartURL(target)
+	if err != nil {
+		return nil, err
+	}
+
+	robotsByHost := make(map[string]*robots.Robots)
+	robotsFor := func(u *url.URL) *robots.Robots {
+		origin := u.Scheme + "://" + u.Host
+		if r, ok := robotsByHost[origin]; ok {
+			return r
+		}
+		r := s.fetchRobots(ctx, origin)
+		robotsByHost[origin] = r
+		return r
+	}
+
+	var queue []string
+	if u.Path == "" || u.Path == "/" {
+		for _, sm := range robotsFor(u).Sitemaps {
+			if resolved, err := u.Parse(sm); err == nil {
+				queue = append(queue, resolved.String())
+			}
+		}
+		if len(queue) == 0 {
+			queue = append(queue, u.Scheme+"://"+u.Host+"/sitemap.xml")
+		}
+	} else {
+		queue = append(queue, u.String())
+	}
+
+	result := &model.SitemapResult{}
+	visited := make(map[string]bool)
+	seen := make(map[string]bool)
+	var entries []string
+
+	for len(queue) > 0 && len(result.Sitemaps) < maxSitemapFiles && !result.Truncated && ctx.Err() == nil {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+		if visited[sitemapURL] {
+			continue
+		}
+		visited[sitemapURL] = true
+
+		parsed, err := s.fetchSitemap(ctx, sitemapURL)
+		if err != nil {
+			log.Printf("Failed to fetch sitemap %s: %v", sitemapURL, err)
+			continue
+		}
+		result.Sitemaps = append(result.Sitemaps, sitemapURL)
+		queue = append(queue, parsed.Sitemaps...)
+
+		for _, entry := range parsed.URLs {
+			if seen[entry] {
+				continue
+			}
+			if len(entries) >= maxLinks {
+				result.Truncated = true
+				break
+			}
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	if len(result.Sitemaps) == 0 {
+		return nil, fmt.Errorf("%w: no sitemap could be fetched for %s", ErrSitemapNotFound, u)
+	}
+
+	log.Printf("Fetched %d sitemaps for %s with %d entries", len(result.Sitemaps), u, len(entries))
+	if result.Truncated {
+		log.Printf("Sitemap check of %s stopped collecting entries at %d", u, maxLinks)
+	}
+
+	checks := make([]model.LinkCheck, len(entries))
+	for i, entry := range entries {
+		checks[i] = s.checkURL(ctx, entry, nil, checkOptions{})
+
+		if entryURL, err := url.Parse(entry); err == nil && !robotsFor(entryURL).Allowed(s.clientSettings.userAgent, entryURL.RequestURI()) {
+			result.Disallowed = append(result.Disallowed, entry)
+		}
+		if checks[i].StatusCode != http.StatusOK {
+			result.NonOK = append(result.NonOK, checks[i])
+		}
+	}
+
+	result.Batch, err = s.saveNewBatch(ctx, checks)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *linkService) fetchSitemap(ctx context.Context, sitemapURL string) (*sitemap.Sitemap, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return sitemap.Parse(io.LimitReader(resp.Body, maxSitemapSize))
+}
+
+func (s *linkService) fetchRobots(ctx context.Context, origin string) *robots.Robots {
+	empty := &robots.Robots{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return empty
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to fetch robots.txt for %s: %v", origin, err)
+		return empty
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return empty
+	}
+
+	parsed, err := robots.Parse(io.LimitReader(resp.Body, maxRobotsSize))
+	if err != nil {
+		log.Printf("Failed to parse robots.txt for %s: %v", origin, err)
+		return empty
+	}
+	return parsed
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+)
+
+func newSitemapSite(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "User-agent: *\nDisallow: /private/\n\nSitemap: %s/sitemap-index.xml\n", server.URL)
+	})
+	mux.HandleFunc("/sitemap-index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/sitemap-pages.xml.gz</loc></sitemap></sitemapindex>`, server.URL)
+	})
+	mux.HandleFunc("/sitemap-pages.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		gz := gzip.NewWriter(w)
+		fmt.Fprintf(gz, `<urlset>
+			<url><loc>%[1]s/</loc></url>
+			<url><loc>%[1]s/private/page</loc></url>
+			<url><loc>%[1]s/gone</loc></url>
+		</urlset>`, server.URL)
+		gz.Close()
+	})
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/private/page", func(w http.ResponseWriter, r *http.Request) {})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLinkService_CheckSitemap_SiteRoot(t *testing.T) {
+	server := newSitemapSite(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	result, err := service.CheckSitemap(context.Background(), server.URL, 0)
+	if err != nil {
+		t.Fatalf("CheckSitemap failed: %v", err)
+	}
+
+	if len(result.Sitemaps) != 2 {
+		t.Errorf("Expected index and nested sitemap to be fetched, got %v", result.Sitemaps)
+	}
+
+	if len(result.Batch.Links) != 3 {
+		t.Errorf("Expected 3 checked links, got %d", len(result.Batch.Links))
+	}
+
+	if len(result.Disallowed) != 1 || result.Disallowed[0] != server.URL+"/private/page" {
+		t.Errorf("Expected /private/page to be disallowed, got %v", result.Disallowed)
+	}
+
+	if len(result.NonOK) != 1 || result.NonOK[0].URL != server.URL+"/gone" || result.NonOK[0].StatusCode != http.StatusNotFound {
+		t.Errorf("Expected /gone to be reported as non-200, got %+v", result.NonOK)
+	}
+}
+
+func TestLinkService_CheckSitemap_MaxLinks(t *testing.T) {
+	server := newSitemapSite(t)
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	result, err := service.CheckSitemap(context.Background(), server.URL, 2)
+	if err != nil {
+		t.Fatalf("CheckSitemap failed: %v", err)
+	}
+	if len(result.Batch.Links) != 2 || !result.Truncated {
+		t.Errorf("Expected 2 checked links and a truncated result, got %d, %v", len(result.Batch.Links), result.Truncated)
+	}
+}
+
+func TestLinkService_CheckSitemap_StopsFetchingWhenTruncated(t *testing.T) {
+	var server *httptest.Server
+	secondFetched := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%[1]s/first.xml</loc></sitemap><sitemap><loc>%[1]s/second.xml</loc></sitemap></sitemapindex>`, server.URL)
+	})
+	mux.HandleFunc("/first.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%[1]s/a</loc></url><url><loc>%[1]s/b</loc></url><url><loc>%[1]s/c</loc></url></urlset>`, server.URL)
+	})
+	mux.HandleFunc("/second.xml", func(w http.ResponseWriter, r *http.Request) {
+		secondFetched = true
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	result, err := service.CheckSitemap(context.Background(), server.URL+"/sitemap.xml", 2)
+	if err != nil {
+		t.Fatalf("CheckSitemap failed: %v", err)
+	}
+	if !result.Truncated || secondFetched {
+		t.Errorf("Expected no sitemaps to be fetched once truncated, got truncated=%v, second fetched=%v", result.Truncated, secondFetched)
+	}
+}
+
+func TestLinkService_CheckSitemap_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	_, err := service.CheckSitemap(context.Background(), server.URL+"/sitemap.xml", 0)
+	if !errors.Is(err, ErrSitemapNotFound) {
+		t.Errorf("Expected ErrSitemapNotFound, got %v", err)
+	}
+}
+
+func TestLinkService_CheckSitemap_RobotsUserAgent(t *testing.T) {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "User-agent: link-checker\nDisallow: /\n\nUser-agent: *\nAllow: /\n\nSitemap: %s/sitemap.xml\n", server.URL)
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%s/page</loc></url></urlset>`, server.URL)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository(), WithUserAgent("link-checker/1.0"))
+
+	result, err := service.CheckSitemap(context.Background(), server.URL, 0)
+	if err != nil {
+		t.Fatalf("CheckSitemap failed: %v", err)
+	}
+	if len(result.Disallowed) != 1 {
+		t.Errorf("Expected the group for the configured user agent to apply, got %v", result.Disallowed)
+	}
+}
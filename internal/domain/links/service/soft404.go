@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	soft404Threshold    = 0.75
+	minEchoedWordLength = 3
+)
+
+var (
+	htmlTitle     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	wordPattern   = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	notFoundTitle = regexp.MustCompile(`(?i)\b(404|not found|page (does not|doesn't) exist|no longer available)\b`)
+)
+
+type pageFingerprint struct {
+	statusCode int
+	finalURL   string
+	title      string
+	words      map[string]bool
+}
+
+type soft404Detector struct {
+	s      *linkService
+	probes map[string]*pageFingerprint
+}
+
+func newSoft404Detector(s *linkService) *soft404Detector {
+	return &soft404Detector{
+		s:      s,
+		probes: make(map[string]*pageFingerprint),
+	}
+}
+
+func (d *soft404Detector) confidence(ctx context.Context, target *url.URL, finalURL string, body []byte) float64 {
+	probe := d.probe(ctx, target)
+	page := fingerprint(http.StatusOK, finalURL, body, target.Path)
+
+	var score float64
+	if probe != nil && probe.statusCode >= 200 && probe.statusCode < 300 {
+		score = similarity(page.words, probe.words)
+		if page.title != "" && page.title == probe.title {
+			score = max(score, 0.5) + 0.25
+		}
+		if page.finalURL == probe.finalURL {
+			score = max(score, 0.95)
+		}
+	}
+	if notFoundTitle.MatchString(page.title) {
+		score = max(score, 0.5) + 0.3
+	}
+	return min(score, 1)
+}
+
+func (d *soft404Detector) probe(ctx context.Context, target *url.URL) *pageFingerprint {
+	dir := path.Dir(target.Path)
+	key := target.Scheme + "://" + target.Host + dir
+	if probe, ok := d.probes[key]; ok {
+		return probe
+	}
+
+	token := make([]byte, 12)
+	rand.Read(token)
+	probeURL := &url.URL{
+		Scheme: target.Scheme,
+		Host:   target.Host,
+		Path:   path.Join(dir, "lc-"+hex.EncodeToString(token)),
+	}
+
+	var probe *pageFingerprint
+	defer func() { d.probes[key] = probe }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", probeURL.String(), nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := d.s.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to probe %s for soft 404: %v", probeURL, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	probe = fingerprint(resp.StatusCode, resp.Request.URL.String(), body, probeURL.Path)
+	return probe
+}
+
+func fingerprint(statusCode int, finalURL string, body []byte, requestPath string) *pageFingerprint {
+	// Pages often echo the requested path, which would skew the comparison.
+	if len(requestPath) > 1 {
+		body = bytes.ReplaceAll(body, []byte(requestPath), nil)
+	}
+	echoed := echoedWords(requestPath)
+
+	page := &pageFingerprint{
+		statusCode: statusCode,
+		finalURL:   finalURL,
+		words:      make(map[string]bool),
+	}
+	if m := htmlTitle.FindSubmatch(body); m != nil {
+		title := wordPattern.ReplaceAllStringFunc(string(m[1]), func(word string) string {
+			if echoed[strings.ToLower(word)] {
+				return ""
+			}
+			return word
+		})
+		page.title = strings.Join(strings.Fields(title), " ")
+	}
+	for _, word := range wordPattern.FindAll(bytes.ToLower(body), -1) {
+		if !echoed[string(word)] {
+			page.words[string(word)] = true
+		}
+	}
+	return page
+}
+
+// echoedWords are the words of the last path segment. Short words are kept,
+// they are too likely to occur in the page on their own.
+func echoedWords(requestPath string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range wordPattern.FindAllString(strings.ToLower(path.Base(requestPath)), -1) {
+		if utf8.RuneCountInString(word) >= minEchoedWordLength {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
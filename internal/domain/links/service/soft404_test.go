@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+const articlePage = `<html><head><title>Installing the agent</title></head>
+<body><h1>Installing the agent</h1><p>Download the package, verify the checksum and run the installer
+with administrator rights. The agent registers itself on first start.</p></body></html>`
+
+func TestLinkService_CheckLinks_Soft404(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/docs/install" {
+			fmt.Fprint(w, articlePage)
+			return
+		}
+		fmt.Fprintf(w, `<html><head><title>Oops</title></head><body><h1>Sorry</h1>
+			<p>We could not find %s. Try the search box or go back home.</p></body></html>`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	urls := []string{server.URL + "/docs/install", server.URL + "/docs/removed-page"}
+	batch, err := service.CheckLinks(context.Background(), urls, WithSoft404Detection())
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusAvailable {
+		t.Errorf("Expected real page to be available, got %s (confidence %.2f)", batch.Links[0].Status, batch.Links[0].Soft404Confidence)
+	}
+
+	if batch.Links[1].Status != model.StatusSoft404 {
+		t.Errorf("Expected missing page to be a soft 404, got %s (confidence %.2f)", batch.Links[1].Status, batch.Links[1].Soft404Confidence)
+	}
+}
+
+func TestLinkService_CheckLinks_Soft404_RealNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/docs/install" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, articlePage)
+	}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	batch, err := service.CheckLinks(context.Background(), []string{server.URL + "/docs/install"}, WithSoft404Detection())
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusAvailable || batch.Links[0].Soft404Confidence != 0 {
+		t.Errorf("Expected available with zero confidence, got %s (confidence %.2f)", batch.Links[0].Status, batch.Links[0].Soft404Confidence)
+	}
+}
+
+func TestLinkService_CheckLinks_Soft404_NotFoundTitle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/old" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Page Not Found</title></head><body>Gone</body></html>`)
+	}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	batch, err := service.CheckLinks(context.Background(), []string{server.URL + "/old"}, WithSoft404Detection())
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusSoft404 {
+		t.Errorf("Expected not-found title to be flagged, got %s (confidence %.2f)", batch.Links[0].Status, batch.Links[0].Soft404Confidence)
+	}
+}
+
+func TestFingerprint_StripsOnlyEchoedWords(t *testing.T) {
+	body := []byte(`<title>Missing widgets</title><p>A banana and widgets at /docs/widgets</p>`)
+
+	page := fingerprint(http.StatusOK, "", body, "/docs/a")
+	for _, word := range []string{"a", "banana", "and", "widgets"} {
+		if !page.words[word] {
+			t.Errorf("Expected %q to be kept for a short path base, got %v", word, page.words)
+		}
+	}
+
+	page = fingerprint(http.StatusOK, "", body, "/docs/widgets")
+	if page.words["widgets"] || page.words["docs"] {
+		t.Errorf("Expected the echoed path to be removed, got %v", page.words)
+	}
+	if !page.words["banana"] || page.title != "Missing" {
+		t.Errorf("Expected other words to be kept, got %v and title %q", page.words, page.title)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+func inspectConnection(host string, state *tls.ConnectionState) *model.TLSInfo {
+	info := inspectCertificates(host, state.PeerCertificates)
+	if info == nil {
+		return nil
+	}
+	info.Version = tlsVersions[state.Version]
+	info.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	return info
+}
+
+func inspectVerificationError(host string, err error) *model.TLSInfo {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil
+	}
+
+	info := inspectCertificates(host, verifyErr.UnverifiedCertificates)
+	if info != nil {
+		info.VerifyError = verifyErr.Err.Error()
+	}
+	return info
+}
+
+func inspectCertificates(host string, certs []*x509.Certificate) *model.TLSInfo {
+	if len(certs) == 0 {
+		return nil
+	}
+
+	info := &model.TLSInfo{}
+	for _, cert := range certs {
+		sans := append([]string(nil), cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		info.Chain = append(info.Chain, model.Certificate{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			SANs:     sans,
+			NotAfter: cert.NotAfter,
+		})
+	}
+
+	leaf := certs[0]
+	info.DaysUntilExpiry = int(math.Floor(time.Until(leaf.NotAfter).Hours() / 24))
+	info.HostnameMismatch = leaf.VerifyHostname(host) != nil
+	info.SelfSigned = bytes.Equal(leaf.RawSubject, leaf.RawIssuer) && leaf.CheckSignatureFrom(leaf) == nil
+	return info
+}
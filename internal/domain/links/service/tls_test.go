@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+func TestLinkService_CheckLinks_CertificateDetails(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository()).(*linkService)
+	svc.client = server.Client()
+
+	batch, err := svc.CheckLinks(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	link := batch.Links[0]
+	if link.Status != model.StatusAvailable {
+		t.Fatalf("Expected available, got %s", link.Status)
+	}
+	if link.TLS == nil || len(link.TLS.Chain) == 0 {
+		t.Fatal("Expected certificate details to be captured")
+	}
+	if link.TLS.Chain[0].Issuer == "" || len(link.TLS.Chain[0].SANs) == 0 {
+		t.Errorf("Expected issuer and SANs, got %+v", link.TLS.Chain[0])
+	}
+	if link.TLS.Version == "" || link.TLS.CipherSuite == "" {
+		t.Errorf("Expected TLS version and cipher, got %q %q", link.TLS.Version, link.TLS.CipherSuite)
+	}
+	if link.TLS.HostnameMismatch {
+		t.Error("Expected certificate to match 127.0.0.1")
+	}
+	if link.TLS.DaysUntilExpiry <= 0 {
+		t.Errorf("Expected certificate to be valid, got %d days", link.TLS.DaysUntilExpiry)
+	}
+}
+
+func TestLinkService_CheckLinks_CertificateExpiringSoon(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	svc := NewLinkService(repository.NewInMemoryLinkRepository(),
+		WithCertExpiryWarning(200*365*24*time.Hour),
+	).(*linkService)
+	svc.client = server.Client()
+
+	batch, err := svc.CheckLinks(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	if batch.Links[0].Status != model.StatusCertExpiring {
+		t.Errorf("Expected %q, got %s", model.StatusCertExpiring, batch.Links[0].Status)
+	}
+}
+
+func TestLinkService_CheckLinks_UntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	service := NewLinkService(repository.NewInMemoryLinkRepository())
+
+	batch, err := service.CheckLinks(context.Background(), []string{server.URL})
+	if err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+
+	link := batch.Links[0]
+	if link.Status != model.StatusNotAvailable {
+		t.Errorf("Expected not available, got %s", link.Status)
+	}
+	if link.TLS == nil || link.TLS.VerifyError == "" {
+		t.Fatalf("Expected certificate details with verification error, got %+v", link.TLS)
+	}
+	if !link.TLS.SelfSigned {
+		t.Error("Expected httptest certificate to be reported as self-signed")
+	}
+}
+
+func TestLinkService_GenerateReport_CertificateSection(t *testing.T) {
+	repo := repository.NewInMemoryLinkRepository()
+	service := NewLinkService(repo)
+
+	repo.SaveBatch(&model.LinkBatch{
+		ID:     "b1",
+		Tenant: tenant.Default,
+		Links: []model.LinkCheck{{
+			URL:    "https://example.com",
+			Status: model.StatusCertExpiring,
+			TLS: &model.TLSInfo{
+				Chain:           []model.Certificate{{Subject: "CN=example.com", Issuer: "CN=Example CA", NotAfter: time.Now().AddDate(0, 0, 5)}},
+				DaysUntilExpiry: 5,
+				Version:         "TLS 1.3",
+				CipherSuite:     "TLS_AES_128_GCM_SHA256",
+			},
+		}},
+	})
+
+	pdfData, err := service.GenerateReport(context.Background(), []string{"b1"})
+	if err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+
+	if len(pdfData) == 0 {
+		t.Error("Expected PDF data, got empty slice")
+	}
+}
@@ -0,0 +1,101 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxSize is the largest uncompressed sitemap accepted, as set by the
+// sitemaps.org protocol.
+const MaxSize = 50 << 20
+
+var ErrTooLarge = errors.New("sitemap is too large")
+
+type Sitemap struct {
+	URLs     []string
+	Sitemaps []string
+}
+
+type document struct {
+	XMLName xml.Name
+	URLs    []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// Parse decodes a plain or gzipped sitemap; the size limit applies to the
+// uncompressed document.
+func Parse(r io.Reader) (*Sitemap, error) {
+	return parse(r, MaxSize)
+}
+
+func parse(r io.Reader, maxSize int64) (*Sitemap, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip sitemap: %w", err)
+		}
+		defer gz.Close()
+		return decode(&sizeLimiter{r: gz, n: maxSize})
+	}
+	return decode(&sizeLimiter{r: buffered, n: maxSize})
+}
+
+// sizeLimiter fails with ErrTooLarge instead of truncating, so an oversized
+// document is not mistaken for a short one.
+type sizeLimiter struct {
+	r io.Reader
+	n int64
+}
+
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, ErrTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+func decode(r io.Reader) (*Sitemap, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode sitemap: %w", err)
+	}
+
+	sitemap := &Sitemap{}
+	switch doc.XMLName.Local {
+	case "urlset":
+		for _, u := range doc.URLs {
+			if loc := strings.TrimSpace(u.Loc); loc != "" {
+				sitemap.URLs = append(sitemap.URLs, loc)
+			}
+		}
+	case "sitemapindex":
+		for _, s := range doc.Sitemaps {
+			if loc := strings.TrimSpace(s.Loc); loc != "" {
+				sitemap.Sitemaps = append(sitemap.Sitemaps, loc)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected sitemap root element %q", doc.XMLName.Local)
+	}
+	return sitemap, nil
+}
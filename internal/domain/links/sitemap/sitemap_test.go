@@ -0,0 +1,79 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const urlset = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc></url>
+  <url><loc> https://example.com/about </loc><lastmod>2025-01-01</lastmod></url>
+</urlset>`
+
+func TestParse_URLSet(t *testing.T) {
+	sitemap, err := Parse(strings.NewReader(urlset))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []string{"https://example.com/", "https://example.com/about"}
+	if !reflect.DeepEqual(sitemap.URLs, expected) {
+		t.Errorf("Expected %v, got %v", expected, sitemap.URLs)
+	}
+}
+
+func TestParse_Index(t *testing.T) {
+	index := `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-1.xml.gz</loc></sitemap>
+</sitemapindex>`
+
+	sitemap, err := Parse(strings.NewReader(index))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(sitemap.Sitemaps) != 1 || sitemap.Sitemaps[0] != "https://example.com/sitemap-1.xml.gz" {
+		t.Errorf("Expected nested sitemap, got %v", sitemap.Sitemaps)
+	}
+}
+
+func TestParse_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(urlset))
+	gz.Close()
+
+	sitemap, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(sitemap.URLs) != 2 {
+		t.Errorf("Expected 2 URLs, got %v", sitemap.URLs)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse(strings.NewReader("<html></html>")); err == nil {
+		t.Error("Expected error for non-sitemap document")
+	}
+}
+
+func TestParse_SizeLimitAppliesToUncompressedData(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("<urlset>" + strings.Repeat("<url><loc>https://example.com/</loc></url>", 1000) + "</urlset>"))
+	gz.Close()
+
+	if _, err := parse(bytes.NewReader(buf.Bytes()), 1024); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge for a small gzip expanding past the limit, got %v", err)
+	}
+	if _, err := parse(strings.NewReader(urlset), int64(len(urlset))); err != nil {
+		t.Errorf("Expected a document of exactly the limit to parse, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeCheckWrite Scope = "check:write"
+	ScopeReportRead Scope = "report:read"
+	ScopeAdmin      Scope = "admin"
+)
+
+var Scopes = []Scope{ScopeCheckWrite, ScopeReportRead, ScopeAdmin}
+
+type APIKey struct {
+	ID         string
+	Name       string
+	Tenant     string
+	Prefix     string
+	Hash       string
+	Scopes     []Scope
+	Quota      Quota
+	Static     bool
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+}
+
+func (k *APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+type AuthMethod string
+
+const (
+	AuthAPIKey AuthMethod = "api_key"
+	AuthJWT    AuthMethod = "jwt"
+)
+
+type Principal struct {
+	Subject string
+	Name    string
+	Tenant  string
+	Method  AuthMethod
+	Scopes  []Scope
+	Quota   Quota
+}
+
+// HasScope reports whether the principal was granted scope; admin grants
+// everything.
+func (p *Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, ScopeAdmin) || slices.Contains(p.Scopes, scope)
+}
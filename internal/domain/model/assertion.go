@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type AssertionType string
+
+const (
+	AssertStatusCode      AssertionType = "status_code"
+	AssertBodyContains    AssertionType = "body_contains"
+	AssertBodyNotContains AssertionType = "body_not_contains"
+	AssertBodyMatches     AssertionType = "body_matches"
+	AssertJSONPathEquals  AssertionType = "json_path_equals"
+	AssertHeaderPresent   AssertionType = "header_present"
+	AssertHeaderEquals    AssertionType = "header_equals"
+	AssertMaxResponseTime AssertionType = "max_response_time"
+)
+
+type Assertion struct {
+	Type            AssertionType
+	StatusCodes     []int
+	Header          string
+	Value           string
+	JSONPath        string
+	JSONValue       any
+	MaxResponseTime time.Duration
+}
@@ -0,0 +1,11 @@
+package model
+
+type Auth struct {
+	Credential string
+	Headers    map[string]string
+}
+
+type HostAuth struct {
+	Hosts []string
+	Auth
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type Certificate struct {
+	Subject  string
+	Issuer   string
+	SANs     []string
+	NotAfter time.Time
+}
+
+type TLSInfo struct {
+	Chain            []Certificate
+	DaysUntilExpiry  int
+	HostnameMismatch bool
+	SelfSigned       bool
+	Version          string
+	CipherSuite      string
+	VerifyError      string
+}
@@ -0,0 +1,18 @@
+package model
+
+type CrawlOptions struct {
+	StartURL   string
+	SameHost   bool
+	PathPrefix string
+	// MaxDepth, MaxPages and MaxLinks are capped at the service limits;
+	// zero or negative values use the limit.
+	MaxDepth int
+	MaxPages int
+	MaxLinks int
+}
+
+type CrawlResult struct {
+	Batch        *LinkBatch
+	PagesCrawled []string
+	Truncated    bool
+}
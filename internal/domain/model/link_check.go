@@ -7,16 +7,38 @@ type LinkStatus string
 const (
 	StatusAvailable    LinkStatus = "available"
 	StatusNotAvailable LinkStatus = "not available"
+	StatusBrokenAnchor LinkStatus = "broken anchor"
+	StatusAssertFailed LinkStatus = "assertion failed"
+	StatusSoft404      LinkStatus = "soft 404"
+	StatusCertExpiring LinkStatus = "certificate expiring soon"
+	StatusBlocked      LinkStatus = "blocked"
+	StatusInvalid      LinkStatus = "invalid"
 )
 
 type LinkCheck struct {
-	URL       string
-	Status    LinkStatus
-	CheckedAt time.Time
+	URL           string
+	NormalizedURL string
+	Scheme        string
+	InsecureOnly  bool
+	Proxy         string
+	Status        LinkStatus
+	StatusCode    int
+	Error         string
+	CheckedAt     time.Time
+	Sources       []string
+
+	ResponseTime      time.Duration
+	FailedAssertions  []string
+	Soft404Confidence float64
+	TLS               *TLSInfo
+	Security          *SecurityAudit
 }
 
 type LinkBatch struct {
-	ID        int
+	ID        string
+	Tenant    string
+	Number    int // sequential within the tenant, identifies the batch in the v1 API
 	Links     []LinkCheck
 	CreatedAt time.Time
-}
\ No newline at end of file
+	Security  *SecuritySummary
+}
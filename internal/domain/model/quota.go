@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Quota limits what a tenant or an API key may consume; zero fields are
+// unlimited.
+type Quota struct {
+	ChecksPerHour     int
+	LinksPerBatch     int
+	ConcurrentBatches int
+	StoredBatches     int
+}
+
+type Usage struct {
+	Checks   int
+	Batches  int
+	Rejected int
+}
+
+type UsageBucket struct {
+	Start time.Time
+	Usage
+}
+
+type SubjectUsage struct {
+	Subject       string
+	Quota         Quota
+	Active        int
+	StoredBatches int
+	History       []UsageBucket
+}
+
+type UsageReport struct {
+	Tenant SubjectUsage
+	Key    *SubjectUsage
+}
@@ -0,0 +1,17 @@
+package model
+
+type SecurityAudit struct {
+	Headers          map[string]string
+	MissingHeaders   []string
+	RedirectsToHTTPS bool
+	MixedContent     []string
+	Score            int
+}
+
+type SecuritySummary struct {
+	Audited              int
+	Score                int
+	MissingHeaders       map[string]int
+	WithoutHTTPSRedirect int
+	WithMixedContent     int
+}
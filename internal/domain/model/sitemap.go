@@ -0,0 +1,9 @@
+package model
+
+type SitemapResult struct {
+	Batch      *LinkBatch
+	Sitemaps   []string
+	Disallowed []string
+	NonOK      []LinkCheck
+	Truncated  bool
+}
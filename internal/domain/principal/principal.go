@@ -0,0 +1,19 @@
+package principal
+
+import (
+	"context"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *model.Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the authenticated caller, nil for anonymous requests.
+func FromContext(ctx context.Context) *model.Principal {
+	p, _ := ctx.Value(contextKey{}).(*model.Principal)
+	return p
+}
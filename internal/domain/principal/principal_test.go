@@ -0,0 +1,19 @@
+package principal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("Expected no principal, got %+v", got)
+	}
+
+	p := &model.Principal{Subject: "k1", Method: model.AuthAPIKey}
+	if got := FromContext(WithPrincipal(context.Background(), p)); got != p {
+		t.Errorf("Expected %+v, got %+v", p, got)
+	}
+}
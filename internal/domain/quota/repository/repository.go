@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+// Retention is how long hourly usage is kept.
+const Retention = 7 * 24 * time.Hour
+
+type UsageRepository interface {
+	// Add accumulates usage into the hourly bucket containing at.
+	Add(subject string, at time.Time, usage model.Usage) error
+	History(subject string, since time.Time) ([]model.UsageBucket, error)
+	// AddActive changes the number of batches in progress and returns the
+	// new value.
+	AddActive(subject string, delta int) (int, error)
+}
+
+type InMemoryUsageRepository struct {
+	mu      sync.Mutex
+	buckets map[string]map[time.Time]model.Usage
+	active  map[string]int
+}
+
+func NewInMemoryUsageRepository() *InMemoryUsageRepository {
+	return &InMemoryUsageRepository{
+		buckets: make(map[string]map[time.Time]model.Usage),
+		active:  make(map[string]int),
+	}
+}
+
+func (r *InMemoryUsageRepository) Add(subject string, at time.Time, usage model.Usage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buckets := r.buckets[subject]
+	if buckets == nil {
+		buckets = make(map[time.Time]model.Usage)
+		r.buckets[subject] = buckets
+	}
+
+	start := at.Truncate(time.Hour)
+	total := buckets[start]
+	total.Checks += usage.Checks
+	total.Batches += usage.Batches
+	total.Rejected += usage.Rejected
+	buckets[start] = total
+
+	for bucket := range buckets {
+		if at.Sub(bucket) > Retention {
+			delete(buckets, bucket)
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryUsageRepository) History(subject string, since time.Time) ([]model.UsageBucket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	since = since.Truncate(time.Hour)
+	var history []model.UsageBucket
+	for start, usage := range r.buckets[subject] {
+		if !start.Before(since) {
+			history = append(history, model.UsageBucket{Start: start, Usage: usage})
+		}
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Start.Before(history[j].Start)
+	})
+	return history, nil
+}
+
+func (r *InMemoryUsageRepository) AddActive(subject string, delta int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	active := r.active[subject] + delta
+	if active <= 0 {
+		delete(r.active, subject)
+		return 0, nil
+	}
+	r.active[subject] = active
+	return active, nil
+}
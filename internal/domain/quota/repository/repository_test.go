@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+func TestInMemoryUsageRepository_History(t *testing.T) {
+	repo := NewInMemoryUsageRepository()
+	now := time.Date(2025, 12, 5, 10, 30, 0, 0, time.UTC)
+
+	repo.Add("tenant:a", now, model.Usage{Checks: 3, Batches: 1})
+	repo.Add("tenant:a", now.Add(10*time.Minute), model.Usage{Checks: 2, Batches: 1})
+	repo.Add("tenant:a", now.Add(-2*time.Hour), model.Usage{Rejected: 1})
+	repo.Add("tenant:b", now, model.Usage{Checks: 100})
+
+	history, err := repo.History("tenant:a", now.Add(-3*time.Hour))
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 buckets, got %+v", history)
+	}
+	if history[0].Rejected != 1 || !history[0].Start.Equal(now.Add(-2*time.Hour).Truncate(time.Hour)) {
+		t.Errorf("Unexpected first bucket: %+v", history[0])
+	}
+	if history[1].Checks != 5 || history[1].Batches != 2 {
+		t.Errorf("Expected usage of one hour to be summed, got %+v", history[1])
+	}
+
+	if recent, _ := repo.History("tenant:a", now); len(recent) != 1 {
+		t.Errorf("Expected only the current hour, got %+v", recent)
+	}
+
+	repo.Add("tenant:a", now.Add(Retention+2*time.Hour), model.Usage{Checks: 1})
+	if all, _ := repo.History("tenant:a", time.Time{}); len(all) != 1 {
+		t.Errorf("Expected old buckets to be dropped, got %+v", all)
+	}
+}
+
+func TestInMemoryUsageRepository_AddActive(t *testing.T) {
+	repo := NewInMemoryUsageRepository()
+
+	if active, _ := repo.AddActive("key:1", 1); active != 1 {
+		t.Errorf("Expected 1 active batch, got %d", active)
+	}
+	if active, _ := repo.AddActive("key:1", 1); active != 2 {
+		t.Errorf("Expected 2 active batches, got %d", active)
+	}
+	repo.AddActive("key:1", -1)
+	if active, _ := repo.AddActive("key:1", -1); active != 0 {
+		t.Errorf("Expected no active batches, got %d", active)
+	}
+	if active, _ := repo.AddActive("key:1", 0); active != 0 {
+		t.Errorf("Expected count not to go below zero, got %d", active)
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+
+	linkservice "github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+// linkService enforces quotas before every operation that creates a batch.
+type linkService struct {
+	linkservice.LinkService
+	quotas QuotaService
+}
+
+func NewLinkService(inner linkservice.LinkService, quotas QuotaService) linkservice.LinkService {
+	return &linkService{LinkService: inner, quotas: quotas}
+}
+
+func (s *linkService) CheckLinks(ctx context.Context, urls []string, opts ...linkservice.CheckOption) (*model.LinkBatch, error) {
+	finish, err := s.quotas.Begin(ctx, len(urls))
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := s.LinkService.CheckLinks(ctx, urls, opts...)
+	finish(batch)
+	return batch, err
+}
+
+// Crawl and CheckSitemap only learn the number of links while running, so
+// they are capped at the links the quotas still allow.
+func (s *linkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	finish, budget, err := s.quotas.BeginUpTo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if budget > 0 && (opts.MaxLinks <= 0 || opts.MaxLinks > budget) {
+		opts.MaxLinks = budget
+	}
+	result, err := s.LinkService.Crawl(ctx, opts)
+	var batch *model.LinkBatch
+	if result != nil {
+		batch = result.Batch
+	}
+	finish(batch)
+	return result, err
+}
+
+func (s *linkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	finish, budget, err := s.quotas.BeginUpTo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if budget > 0 && (maxLinks <= 0 || maxLinks > budget) {
+		maxLinks = budget
+	}
+	result, err := s.LinkService.CheckSitemap(ctx, target, maxLinks)
+	var batch *model.LinkBatch
+	if result != nil {
+		batch = result.Batch
+	}
+	finish(batch)
+	return result, err
+}
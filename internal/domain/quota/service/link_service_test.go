@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	linkservice "github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+)
+
+type stubLinkService struct {
+	linkservice.LinkService
+	calls    int
+	maxLinks int
+}
+
+func (s *stubLinkService) CheckLinks(ctx context.Context, urls []string, opts ...linkservice.CheckOption) (*model.LinkBatch, error) {
+	s.calls++
+	return batchOf(len(urls)), nil
+}
+
+func (s *stubLinkService) Crawl(ctx context.Context, opts model.CrawlOptions) (*model.CrawlResult, error) {
+	s.calls++
+	s.maxLinks = opts.MaxLinks
+	return nil, errors.New("crawl failed")
+}
+
+func (s *stubLinkService) CheckSitemap(ctx context.Context, target string, maxLinks int) (*model.SitemapResult, error) {
+	s.calls++
+	s.maxLinks = maxLinks
+	return &model.SitemapResult{Batch: batchOf(maxLinks)}, nil
+}
+
+func TestLinkService_EnforcesQuota(t *testing.T) {
+	quotas := newTestService(0, WithDefaultQuota(model.Quota{ChecksPerHour: 3}))
+	inner := &stubLinkService{}
+	svc := NewLinkService(inner, quotas)
+	ctx := context.Background()
+
+	if _, err := svc.CheckLinks(ctx, []string{"a", "b"}); err != nil {
+		t.Fatalf("CheckLinks failed: %v", err)
+	}
+	if _, err := svc.CheckLinks(ctx, []string{"a", "b"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected quota error, got %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("Expected rejected batch not to be checked, got %d calls", inner.calls)
+	}
+
+	if _, err := svc.Crawl(ctx, model.CrawlOptions{}); err == nil {
+		t.Error("Expected crawl error to be returned")
+	}
+	if report, _ := quotas.Usage(ctx, quotas.now()); report.Tenant.Active != 0 {
+		t.Errorf("Expected failed crawl to release its slot, got %d active", report.Tenant.Active)
+	}
+}
+
+func TestLinkService_CapsLinksAtBudget(t *testing.T) {
+	quotas := newTestService(0, WithDefaultQuota(model.Quota{ChecksPerHour: 10, LinksPerBatch: 8}))
+	inner := &stubLinkService{}
+	svc := NewLinkService(inner, quotas)
+	ctx := context.Background()
+
+	svc.Crawl(ctx, model.CrawlOptions{MaxLinks: 5})
+	if inner.maxLinks != 5 {
+		t.Errorf("Expected a smaller requested limit to be kept, got %d", inner.maxLinks)
+	}
+	svc.Crawl(ctx, model.CrawlOptions{})
+	if inner.maxLinks != 8 {
+		t.Errorf("Expected crawl to be capped at links_per_batch, got %d", inner.maxLinks)
+	}
+
+	if _, err := svc.CheckSitemap(ctx, "https://example.com", 0); err != nil {
+		t.Fatalf("CheckSitemap failed: %v", err)
+	}
+	if inner.maxLinks != 8 {
+		t.Errorf("Expected sitemap to be capped at links_per_batch, got %d", inner.maxLinks)
+	}
+	if _, err := svc.CheckSitemap(ctx, "https://example.com", 0); err != nil {
+		t.Fatalf("CheckSitemap failed: %v", err)
+	}
+	if inner.maxLinks != 2 {
+		t.Errorf("Expected sitemap to be capped at the remaining hourly checks, got %d", inner.maxLinks)
+	}
+}
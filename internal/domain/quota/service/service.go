@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/principal"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/quota/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// concurrencyRetryAfter is suggested to clients waiting for one of their
+// batches to finish.
+const concurrencyRetryAfter = 5 * time.Second
+
+// ExceededError names the exhausted quota. RetryAfter is zero when waiting
+// does not help.
+type ExceededError struct {
+	Subject    string
+	Quota      string
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %d reached for %s", ErrQuotaExceeded, e.Quota, e.Limit, e.Subject)
+}
+
+func (e *ExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+type BatchCounter interface {
+	CountBatches(tenant string) (int, error)
+}
+
+// Finish records the outcome of a batch started with Begin; batch is nil
+// when the batch failed.
+type Finish func(batch *model.LinkBatch)
+
+type QuotaService interface {
+	// Begin admits a batch of links and reserves them until it finishes.
+	Begin(ctx context.Context, links int) (Finish, error)
+	// BeginUpTo admits a batch whose size is only known while it runs. It
+	// reserves every link the quotas still allow and returns that budget,
+	// zero when unlimited.
+	BeginUpTo(ctx context.Context) (Finish, int, error)
+	Usage(ctx context.Context, since time.Time) (*model.UsageReport, error)
+	// Configure replaces the quotas. Recorded usage and running batches are
+	// kept.
+	Configure(opts ...Option)
+}
+
+type Option func(*quotaService)
+
+func WithDefaultQuota(quota model.Quota) Option {
+	return func(s *quotaService) {
+		s.defaults = quota
+	}
+}
+
+// WithTenantQuota overrides the non-zero limits for one tenant.
+func WithTenantQuota(tenantID string, quota model.Quota) Option {
+	return func(s *quotaService) {
+		s.tenants[tenantID] = quota
+	}
+}
+
+type quotaService struct {
+	mu       sync.Mutex
+	repo     repository.UsageRepository
+	batches  BatchCounter
+	defaults model.Quota
+	tenants  map[string]model.Quota
+	now      func() time.Time
+}
+
+func NewQuotaService(repo repository.UsageRepository, batches BatchCounter, opts ...Option) QuotaService {
+	s := &quotaService{
+		repo:    repo,
+		batches: batches,
+		now:     time.Now,
+	}
+	s.Configure(opts...)
+	return s
+}
+
+func (s *quotaService) Configure(opts ...Option) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaults = model.Quota{}
+	s.tenants = make(map[string]model.Quota)
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+// subject is a tenant or an API key that usage is accounted to.
+type subject struct {
+	id     string
+	tenant string
+	quota  model.Quota
+}
+
+func (s *quotaService) subjects(ctx context.Context) []subject {
+	tenantID := tenant.FromContext(ctx)
+	subjects := []subject{{id: "tenant:" + tenantID, tenant: tenantID, quota: s.tenantQuota(tenantID)}}
+	if caller := principal.FromContext(ctx); caller != nil && caller.Method == model.AuthAPIKey {
+		subjects = append(subjects, subject{id: "key:" + caller.Subject, quota: caller.Quota})
+	}
+	return subjects
+}
+
+func (s *quotaService) tenantQuota(tenantID string) model.Quota {
+	quota := s.defaults
+	override := s.tenants[tenantID]
+	if override.ChecksPerHour > 0 {
+		quota.ChecksPerHour = override.ChecksPerHour
+	}
+	if override.LinksPerBatch > 0 {
+		quota.LinksPerBatch = override.LinksPerBatch
+	}
+	if override.ConcurrentBatches > 0 {
+		quota.ConcurrentBatches = override.ConcurrentBatches
+	}
+	if override.StoredBatches > 0 {
+		quota.StoredBatches = override.StoredBatches
+	}
+	return quota
+}
+
+func (s *quotaService) Begin(ctx context.Context, links int) (Finish, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.begin(s.subjects(ctx), links, s.now())
+}
+
+func (s *quotaService) BeginUpTo(ctx context.Context) (Finish, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	subjects := s.subjects(ctx)
+	budget := -1
+	for _, sub := range subjects {
+		left, err := s.budget(sub, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		if left >= 0 && (budget < 0 || left < budget) {
+			budget = left
+		}
+	}
+
+	finish, err := s.begin(subjects, max(budget, 0), now)
+	if err != nil {
+		return nil, 0, err
+	}
+	return finish, max(budget, 0), nil
+}
+
+func (s *quotaService) begin(subjects []subject, links int, now time.Time) (Finish, error) {
+	for _, sub := range subjects {
+		if err := s.check(sub, links, now); err != nil {
+			log.Printf("Rejected batch of %d links: %v", links, err)
+			for _, sub := range subjects {
+				if err := s.repo.Add(sub.id, now, model.Usage{Rejected: 1}); err != nil {
+					log.Printf("Failed to record usage of %s: %v", sub.id, err)
+				}
+			}
+			return nil, err
+		}
+	}
+
+	// The links are counted right away so that concurrent batches cannot
+	// overrun the hourly quota; finish corrects the count.
+	for _, sub := range subjects {
+		if _, err := s.repo.AddActive(sub.id, 1); err != nil {
+			return nil, fmt.Errorf("failed to record active batch: %w", err)
+		}
+		if err := s.repo.Add(sub.id, now, model.Usage{Checks: links}); err != nil {
+			return nil, fmt.Errorf("failed to reserve checks: %w", err)
+		}
+	}
+
+	var once sync.Once
+	return func(batch *model.LinkBatch) {
+		once.Do(func() { s.finish(subjects, links, now, batch) })
+	}, nil
+}
+
+// budget is the number of links sub may still check in one batch, -1 when
+// unlimited.
+func (s *quotaService) budget(sub subject, now time.Time) (int, error) {
+	budget := -1
+	if sub.quota.LinksPerBatch > 0 {
+		budget = sub.quota.LinksPerBatch
+	}
+	if sub.quota.ChecksPerHour > 0 {
+		used, err := s.checksThisHour(sub.id, now)
+		if err != nil {
+			return 0, err
+		}
+		left := max(sub.quota.ChecksPerHour-used, 0)
+		if budget < 0 || left < budget {
+			budget = left
+		}
+	}
+	return budget, nil
+}
+
+func (s *quotaService) checksThisHour(subjectID string, now time.Time) (int, error) {
+	history, err := s.repo.History(subjectID, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read usage: %w", err)
+	}
+	used := 0
+	for _, bucket := range history {
+		used += bucket.Checks
+	}
+	return used, nil
+}
+
+func (s *quotaService) check(sub subject, links int, now time.Time) error {
+	quota := sub.quota
+	exceeded := func(name string, limit int, retryAfter time.Duration) error {
+		return &ExceededError{Subject: sub.id, Quota: name, Limit: limit, RetryAfter: retryAfter}
+	}
+
+	if quota.LinksPerBatch > 0 && links > quota.LinksPerBatch {
+		return exceeded("links_per_batch", quota.LinksPerBatch, 0)
+	}
+
+	if quota.ChecksPerHour > 0 {
+		used, err := s.checksThisHour(sub.id, now)
+		if err != nil {
+			return err
+		}
+		if used+max(links, 1) > quota.ChecksPerHour {
+			var retryAfter time.Duration
+			if links <= quota.ChecksPerHour {
+				retryAfter = now.Truncate(time.Hour).Add(time.Hour).Sub(now)
+			}
+			return exceeded("checks_per_hour", quota.ChecksPerHour, retryAfter)
+		}
+	}
+
+	if quota.ConcurrentBatches > 0 {
+		active, err := s.repo.AddActive(sub.id, 0)
+		if err != nil {
+			return fmt.Errorf("failed to read active batches: %w", err)
+		}
+		if active >= quota.ConcurrentBatches {
+			return exceeded("concurrent_batches", quota.ConcurrentBatches, concurrencyRetryAfter)
+		}
+	}
+
+	if quota.StoredBatches > 0 && sub.tenant != "" {
+		stored, err := s.batches.CountBatches(sub.tenant)
+		if err != nil {
+			return fmt.Errorf("failed to count batches: %w", err)
+		}
+		if stored >= quota.StoredBatches {
+			return exceeded("stored_batches", quota.StoredBatches, 0)
+		}
+	}
+
+	return nil
+}
+
+func (s *quotaService) finish(subjects []subject, reserved int, begun time.Time, batch *model.LinkBatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var usage model.Usage
+	if batch != nil {
+		usage = model.Usage{Checks: len(batch.Links), Batches: 1}
+	}
+
+	now := s.now()
+	for _, sub := range subjects {
+		if _, err := s.repo.AddActive(sub.id, -1); err != nil {
+			log.Printf("Failed to release active batch of %s: %v", sub.id, err)
+		}
+		if err := s.repo.Add(sub.id, begun, model.Usage{Checks: -reserved}); err != nil {
+			log.Printf("Failed to release reserved checks of %s: %v", sub.id, err)
+		}
+		if err := s.repo.Add(sub.id, now, usage); err != nil {
+			log.Printf("Failed to record usage of %s: %v", sub.id, err)
+		}
+	}
+}
+
+func (s *quotaService) Usage(ctx context.Context, since time.Time) (*model.UsageReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var report model.UsageReport
+	for _, sub := range s.subjects(ctx) {
+		usage := model.SubjectUsage{Subject: sub.id, Quota: sub.quota}
+
+		var err error
+		if usage.History, err = s.repo.History(sub.id, since); err != nil {
+			return nil, fmt.Errorf("failed to read usage: %w", err)
+		}
+		if usage.Active, err = s.repo.AddActive(sub.id, 0); err != nil {
+			return nil, fmt.Errorf("failed to read active batches: %w", err)
+		}
+
+		if sub.tenant == "" {
+			report.Key = &usage
+			continue
+		}
+		if usage.StoredBatches, err = s.batches.CountBatches(sub.tenant); err != nil {
+			return nil, fmt.Errorf("failed to count batches: %w", err)
+		}
+		report.Tenant = usage
+	}
+	return &report, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/principal"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/quota/repository"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+type batchCount int
+
+func (c batchCount) CountBatches(tenant string) (int, error) {
+	return int(c), nil
+}
+
+func newTestService(stored int, opts ...Option) *quotaService {
+	s := NewQuotaService(repository.NewInMemoryUsageRepository(), batchCount(stored), opts...).(*quotaService)
+	s.now = func() time.Time { return time.Date(2025, 12, 5, 10, 45, 0, 0, time.UTC) }
+	return s
+}
+
+func batchOf(links int) *model.LinkBatch {
+	return &model.LinkBatch{Links: make([]model.LinkCheck, links)}
+}
+
+func expectExceeded(t *testing.T, err error, quota string, retryAfter time.Duration) {
+	t.Helper()
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected %s quota to be exceeded, got %v", quota, err)
+	}
+	if exceeded.Quota != quota || exceeded.RetryAfter != retryAfter {
+		t.Errorf("Expected %s with retry after %v, got %+v", quota, retryAfter, exceeded)
+	}
+}
+
+func TestQuotaService_ChecksPerHour(t *testing.T) {
+	svc := newTestService(0, WithDefaultQuota(model.Quota{ChecksPerHour: 10}))
+	ctx := context.Background()
+
+	finish, err := svc.Begin(ctx, 6)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	finish(batchOf(6))
+
+	_, err = svc.Begin(ctx, 5)
+	expectExceeded(t, err, "checks_per_hour", 15*time.Minute)
+
+	_, err = svc.Begin(ctx, 11)
+	expectExceeded(t, err, "checks_per_hour", 0)
+
+	if _, err := svc.Begin(ctx, 4); err != nil {
+		t.Errorf("Expected remaining checks to be usable, got %v", err)
+	}
+}
+
+func TestQuotaService_ReservesChecks(t *testing.T) {
+	svc := newTestService(0, WithDefaultQuota(model.Quota{ChecksPerHour: 10}))
+	ctx := context.Background()
+
+	finish, err := svc.Begin(ctx, 6)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	_, err = svc.Begin(ctx, 5)
+	expectExceeded(t, err, "checks_per_hour", 15*time.Minute)
+
+	// Only the links actually checked are kept.
+	finish(batchOf(2))
+	if _, err := svc.Begin(ctx, 8); err != nil {
+		t.Errorf("Expected unused reserved checks to be released, got %v", err)
+	}
+}
+
+func TestQuotaService_BeginUpTo(t *testing.T) {
+	svc := newTestService(0, WithDefaultQuota(model.Quota{ChecksPerHour: 10, LinksPerBatch: 8}))
+	ctx := context.Background()
+
+	finish, budget, err := svc.BeginUpTo(ctx)
+	if err != nil || budget != 8 {
+		t.Fatalf("Expected a budget of links_per_batch, got %d, %v", budget, err)
+	}
+	if _, err := svc.Begin(ctx, 3); err == nil {
+		t.Error("Expected the budget to be reserved")
+	}
+	finish(batchOf(7))
+
+	finish, budget, err = svc.BeginUpTo(ctx)
+	if err != nil || budget != 3 {
+		t.Fatalf("Expected a budget of the remaining hourly checks, got %d, %v", budget, err)
+	}
+	finish(batchOf(3))
+
+	_, _, err = svc.BeginUpTo(ctx)
+	expectExceeded(t, err, "checks_per_hour", 15*time.Minute)
+
+	unlimited := newTestService(0)
+	if _, budget, err := unlimited.BeginUpTo(ctx); err != nil || budget != 0 {
+		t.Errorf("Expected no budget without quotas, got %d, %v", budget, err)
+	}
+}
+
+func TestQuotaService_Limits(t *testing.T) {
+	svc := newTestService(3,
+		WithDefaultQuota(model.Quota{LinksPerBatch: 2, ConcurrentBatches: 1}),
+		WithTenantQuota("team-a", model.Quota{LinksPerBatch: 5, StoredBatches: 3}),
+	)
+	shared := context.Background()
+	teamA := tenant.WithTenant(context.Background(), "team-a")
+
+	_, err := svc.Begin(shared, 3)
+	expectExceeded(t, err, "links_per_batch", 0)
+
+	finish, err := svc.Begin(shared, 2)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	_, err = svc.Begin(shared, 1)
+	expectExceeded(t, err, "concurrent_batches", concurrencyRetryAfter)
+	finish(nil)
+	finish(nil)
+	if _, err := svc.Begin(shared, 1); err != nil {
+		t.Errorf("Expected batch to be admitted after the previous one finished, got %v", err)
+	}
+
+	_, err = svc.Begin(teamA, 5)
+	expectExceeded(t, err, "stored_batches", 0)
+}
+
+func TestQuotaService_KeyQuota(t *testing.T) {
+	svc := newTestService(0)
+	key := &model.Principal{Subject: "k1", Method: model.AuthAPIKey, Quota: model.Quota{LinksPerBatch: 1}}
+	ctx := principal.WithPrincipal(context.Background(), key)
+
+	_, err := svc.Begin(ctx, 2)
+	expectExceeded(t, err, "links_per_batch", 0)
+
+	report, err := svc.Usage(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if report.Key == nil || report.Key.Subject != "key:k1" || report.Key.Quota.LinksPerBatch != 1 {
+		t.Fatalf("Expected key usage, got %+v", report.Key)
+	}
+	if len(report.Tenant.History) != 1 || report.Tenant.History[0].Rejected != 1 {
+		t.Errorf("Expected rejection to be recorded for the tenant, got %+v", report.Tenant.History)
+	}
+}
+
+func TestQuotaService_Usage(t *testing.T) {
+	svc := newTestService(2)
+	ctx := tenant.WithTenant(context.Background(), "team-a")
+
+	finish, _ := svc.Begin(ctx, 3)
+	finish(batchOf(3))
+	svc.Begin(ctx, 1)
+
+	report, err := svc.Usage(ctx, svc.now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	usage := report.Tenant
+	if usage.Subject != "tenant:team-a" || usage.Active != 1 || usage.StoredBatches != 2 || report.Key != nil {
+		t.Errorf("Unexpected usage: %+v", report)
+	}
+	// The link of the batch in progress is reserved.
+	if len(usage.History) != 1 || usage.History[0].Checks != 4 || usage.History[0].Batches != 1 {
+		t.Errorf("Unexpected history: %+v", usage.History)
+	}
+}
@@ -0,0 +1,20 @@
+package tenant
+
+import "context"
+
+// Default owns everything created by callers without a tenant, including all
+// requests when authentication is disabled.
+const Default = "default"
+
+type contextKey struct{}
+
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
@@ -0,0 +1,18 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("Expected %q without tenant, got %q", Default, got)
+	}
+	if got := FromContext(WithTenant(context.Background(), "")); got != Default {
+		t.Errorf("Expected %q for empty tenant, got %q", Default, got)
+	}
+	if got := FromContext(WithTenant(context.Background(), "team-a")); got != "team-a" {
+		t.Errorf("Expected team-a, got %q", got)
+	}
+}
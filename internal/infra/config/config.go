@@ -1,19 +1,292 @@
 package config
 
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
 type Config struct {
-	Server ServerConfig
+	Server    ServerConfig    `yaml:"server"`
+	API       APIConfig       `yaml:"api"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Quota     QuotaConfig     `yaml:"quota"`
+	Tenants   []TenantConfig  `yaml:"tenants"`
+	Checker   CheckerConfig   `yaml:"checker"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Logging   LoggingConfig   `yaml:"logging"`
+	Reload    ReloadConfig    `yaml:"reload"`
+}
+
+type AuthConfig struct {
+	Enabled bool        `yaml:"enabled"`
+	Keys    []KeyConfig `yaml:"keys"`
+	JWT     JWTConfig   `yaml:"jwt"`
+}
+
+type JWTConfig struct {
+	JWKSURL     string            `yaml:"jwks_url"`
+	Issuer      string            `yaml:"issuer"`
+	Audience    string            `yaml:"audience"`
+	Leeway      time.Duration     `yaml:"leeway"`
+	CacheTTL    time.Duration     `yaml:"cache_ttl"`
+	ScopeClaim  string            `yaml:"scope_claim"`
+	ScopeMap    map[string]string `yaml:"scope_map"`
+	TenantClaim string            `yaml:"tenant_claim"`
+}
+
+type KeyConfig struct {
+	Name   string      `yaml:"name"`
+	Tenant string      `yaml:"tenant"`
+	Hash   string      `yaml:"hash"`
+	Scopes []string    `yaml:"scopes"`
+	Quota  QuotaConfig `yaml:"quota"`
+}
+
+// RateLimitConfig is a token bucket per key: burst requests at once and
+// refill_rate more every refill_period.
+type RateLimitConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Key          string        `yaml:"key"`
+	Burst        int           `yaml:"burst"`
+	RefillRate   int           `yaml:"refill_rate"`
+	RefillPeriod time.Duration `yaml:"refill_period"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+}
+
+// QuotaConfig limits consumption; zero values are unlimited.
+type QuotaConfig struct {
+	ChecksPerHour     int `yaml:"checks_per_hour"`
+	LinksPerBatch     int `yaml:"links_per_batch"`
+	ConcurrentBatches int `yaml:"concurrent_batches"`
+	StoredBatches     int `yaml:"stored_batches"`
+}
+
+// TenantConfig overrides settings for one tenant; zero values inherit the
+// global setting.
+type TenantConfig struct {
+	Name  string      `yaml:"name"`
+	API   APIConfig   `yaml:"api"`
+	Quota QuotaConfig `yaml:"quota"`
 }
 
 type ServerConfig struct {
-	Host string
-	Port string
+	Host              string        `yaml:"host"`
+	Port              string        `yaml:"port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
 }
 
-func LoadConfig(configPath string) (*Config, error) {
+type APIConfig struct {
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	MaxLinks     int   `yaml:"max_links"`
+	MaxURLLength int   `yaml:"max_url_length"`
+}
+
+type CheckerConfig struct {
+	Timeout             time.Duration      `yaml:"timeout"`
+	CertExpiryWarning   time.Duration      `yaml:"cert_expiry_warning"`
+	StripTrackingParams bool               `yaml:"strip_tracking_params"`
+	SchemeStrategy      string             `yaml:"scheme_strategy"`
+	HTTP                HTTPClientConfig   `yaml:"http"`
+	Proxy               ProxyConfig        `yaml:"proxy"`
+	Crawl               CrawlConfig        `yaml:"crawl"`
+	Credentials         []CredentialConfig `yaml:"credentials"`
+	Destination         DestinationConfig  `yaml:"destination"`
+}
+
+// CrawlConfig caps what a single crawl request may ask for.
+type CrawlConfig struct {
+	MaxPages int `yaml:"max_pages"`
+	MaxDepth int `yaml:"max_depth"`
+	MaxLinks int `yaml:"max_links"`
+}
+
+// CredentialConfig is sent only to hosts matching Hosts and usable only by
+// Tenant, the "default" tenant when empty.
+type CredentialConfig struct {
+	Name     string   `yaml:"name"`
+	Tenant   string   `yaml:"tenant"`
+	Hosts    []string `yaml:"hosts"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	Token    string   `yaml:"token"`
+	Headers  []string `yaml:"headers"`
+}
+
+type ProxyConfig struct {
+	URL             string      `yaml:"url"`
+	FromEnvironment bool        `yaml:"from_environment"`
+	NoProxy         []string    `yaml:"no_proxy"`
+	Rules           []ProxyRule `yaml:"rules"`
+}
+
+type ProxyRule struct {
+	Hosts []string `yaml:"hosts"`
+	URL   string   `yaml:"url"`
+}
+
+type HTTPClientConfig struct {
+	ConnectTimeout        time.Duration `yaml:"connect_timeout"`
+	TLSHandshakeTimeout   time.Duration `yaml:"tls_handshake_timeout"`
+	ResponseHeaderTimeout time.Duration `yaml:"response_header_timeout"`
+	MaxIdleConnsPerHost   int           `yaml:"max_idle_conns_per_host"`
+	KeepAlive             time.Duration `yaml:"keep_alive"`
+	HTTP2                 bool          `yaml:"http2"`
+	UserAgent             string        `yaml:"user_agent"`
+	Headers               []string      `yaml:"headers"`
+	TLSMinVersion         string        `yaml:"tls_min_version"`
+	CABundle              string        `yaml:"ca_bundle"`
+	InsecureSkipVerify    bool          `yaml:"insecure_skip_verify"`
+}
+
+type DestinationConfig struct {
+	AllowPrivate   bool     `yaml:"allow_private"`
+	AllowCIDRs     []string `yaml:"allow_cidrs"`
+	DenyCIDRs      []string `yaml:"deny_cidrs"`
+	AllowHosts     []string `yaml:"allow_hosts"`
+	DenyHosts      []string `yaml:"deny_hosts"`
+	AllowedPorts   []int    `yaml:"allowed_ports"`
+	AllowedSchemes []string `yaml:"allowed_schemes"`
+}
+
+type StorageConfig struct {
+	Backend string `yaml:"backend"`
+}
+
+type LoggingConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+func Default() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Host: "localhost",
-			Port: "8080",
+			Host:              "localhost",
+			Port:              "8080",
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			WriteTimeout:      5 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		API: APIConfig{
+			MaxBodyBytes: 1 << 20,
+			MaxLinks:     1000,
+			MaxURLLength: 2048,
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				Leeway:     30 * time.Second,
+				CacheTTL:   10 * time.Minute,
+				ScopeClaim: "scope",
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Key:          "ip",
+			Burst:        60,
+			RefillRate:   1,
+			RefillPeriod: time.Second,
+			IdleTimeout:  10 * time.Minute,
 		},
-	}, nil
+		Checker: CheckerConfig{
+			Timeout:           10 * time.Second,
+			CertExpiryWarning: 30 * 24 * time.Hour,
+			SchemeStrategy:    "https-then-http",
+			Crawl: CrawlConfig{
+				MaxPages: 100,
+				MaxDepth: 5,
+				MaxLinks: 1000,
+			},
+			HTTP: HTTPClientConfig{
+				ConnectTimeout:      30 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+				MaxIdleConnsPerHost: 10,
+				KeepAlive:           30 * time.Second,
+				HTTP2:               true,
+				UserAgent:           "link-checker/1.0",
+				TLSMinVersion:       "1.2",
+			},
+			Destination: DestinationConfig{
+				AllowedSchemes: []string{"http", "https"},
+			},
+		},
+		Storage: StorageConfig{
+			Backend: "memory",
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Reload: ReloadConfig{
+			WatchInterval: 10 * time.Second,
+		},
+	}
+}
+
+type ReloadConfig struct {
+	WatchInterval time.Duration `yaml:"watch_interval"`
+}
+
+type Loader struct {
+	Path      string
+	Overrides []string
+	LookupEnv func(string) (string, bool)
+}
+
+func LoadConfig(configPath string) (*Config, error) {
+	loader := &Loader{Path: configPath}
+	return loader.Load()
+}
+
+func (l *Loader) Load() (*Config, error) {
+	cfg := Default()
+
+	if l.Path != "" {
+		if err := loadFile(l.Path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	lookupEnv := l.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	if err := applyEnv(cfg, lookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := applyOverrides(cfg, l.Overrides); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
 }
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+}
+
+func Diff(old, next *Config) []Change {
+	oldFields := settings(old)
+	newFields := settings(next)
+
+	var changes []Change
+	for key, oldValue := range oldFields {
+		newValue := newFields[key]
+		if equal(oldValue, newValue) {
+			continue
+		}
+		change := Change{
+			Key: key,
+			Old: redactCredentials(fmt.Sprint(oldValue.Interface())),
+			New: redactCredentials(fmt.Sprint(newValue.Interface())),
+		}
+		if secretKeys[key] {
+			change.Old, change.New = "[redacted]", "[redacted]"
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// Default headers may carry tokens such as "Authorization: Bearer ...".
+var secretKeys = map[string]bool{
+	"checker.credentials":  true,
+	"checker.http.headers": true,
+}
+
+var credentials = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+
+// Proxy URLs may carry passwords; changes are logged on reload.
+func redactCredentials(value string) string {
+	return credentials.ReplaceAllString(value, "${1}xxxxx@")
+}
+
+func equal(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "LINKCHECK_"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func settings(cfg *Config) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		for i := 0; i < v.NumField(); i++ {
+			key := v.Type().Field(i).Tag.Get("yaml")
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			field := v.Field(i)
+			if field.Kind() == reflect.Struct {
+				walk(field, key)
+				continue
+			}
+			fields[key] = field
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+
+	return fields
+}
+
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func applyEnv(cfg *Config, lookupEnv func(string) (string, bool)) error {
+	fields := settings(cfg)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := EnvName(key)
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(fields[key], value); err != nil {
+			return fmt.Errorf("%s (from %s): %w", key, name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyOverrides(cfg *Config, overrides []string) error {
+	fields := settings(cfg)
+
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return fmt.Errorf("invalid override %q: expected key=value", override)
+		}
+
+		field, ok := fields[strings.TrimSpace(key)]
+		if !ok {
+			return fmt.Errorf("%s: unknown configuration key", key)
+		}
+		if err := setValue(field, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func setValue(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		var parts []string
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+
+	return nil
+}
+
+type overrideFlag []string
+
+func (f *overrideFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *overrideFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func ParseFlags(args []string) (*Loader, error) {
+	fs := flag.NewFlagSet("link-checker", flag.ContinueOnError)
+
+	loader := &Loader{}
+	fs.StringVar(&loader.Path, "config", os.Getenv(envPrefix+"CONFIG"), "path to a YAML or JSON config file")
+	fs.Var((*overrideFlag)(&loader.Overrides), "set", "override a config key, e.g. -set server.port=9090 (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	return loader, nil
+}
@@ -0,0 +1,291 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/netpolicy"
+	"github.com/eightjhonydolly/05.12.2025/internal/infra/proxy"
+)
+
+var (
+	tlsVersions      = []string{"1.0", "1.1", "1.2", "1.3"}
+	schemeStrategies = []string{"https-only", "https-then-http", "http-only", "both"}
+	storageBackends  = []string{"memory"}
+	logLevels        = []string{"debug", "info", "warn", "error"}
+	logFormats       = []string{"text", "json"}
+	rateLimitKeys    = []string{"ip", "api_key", "route"}
+)
+
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("server.port", "must be a number between 1 and 65535, got %q", c.Server.Port)
+	}
+	timeouts := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+		{"reload.watch_interval", c.Reload.WatchInterval},
+		{"checker.http.connect_timeout", c.Checker.HTTP.ConnectTimeout},
+		{"checker.http.tls_handshake_timeout", c.Checker.HTTP.TLSHandshakeTimeout},
+		{"checker.http.response_header_timeout", c.Checker.HTTP.ResponseHeaderTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			fail(timeout.key, "must not be negative")
+		}
+	}
+
+	if c.API.MaxBodyBytes <= 0 {
+		fail("api.max_body_bytes", "must be positive")
+	}
+	if c.API.MaxLinks <= 0 {
+		fail("api.max_links", "must be positive")
+	}
+	if c.API.MaxURLLength <= 0 {
+		fail("api.max_url_length", "must be positive")
+	}
+
+	rateLimit := c.RateLimit
+	if !slices.Contains(rateLimitKeys, rateLimit.Key) {
+		fail("rate_limit.key", "must be one of %s, got %q", strings.Join(rateLimitKeys, ", "), rateLimit.Key)
+	}
+	if rateLimit.Burst <= 0 {
+		fail("rate_limit.burst", "must be positive")
+	}
+	if rateLimit.RefillRate <= 0 {
+		fail("rate_limit.refill_rate", "must be positive")
+	}
+	if rateLimit.RefillPeriod <= 0 {
+		fail("rate_limit.refill_period", "must be positive")
+	}
+	if rateLimit.IdleTimeout <= 0 {
+		fail("rate_limit.idle_timeout", "must be positive")
+	}
+
+	validateQuota("quota", c.Quota, fail)
+
+	tenantNames := make(map[string]bool)
+	for i, tenant := range c.Tenants {
+		prefix := fmt.Sprintf("tenants[%d]", i)
+		switch {
+		case tenant.Name == "":
+			fail(prefix+".name", "must not be empty")
+		case tenantNames[tenant.Name]:
+			fail(prefix+".name", "duplicate tenant %q", tenant.Name)
+		}
+		tenantNames[tenant.Name] = true
+
+		if tenant.API.MaxBodyBytes < 0 {
+			fail(prefix+".api.max_body_bytes", "must not be negative")
+		}
+		if tenant.API.MaxLinks < 0 {
+			fail(prefix+".api.max_links", "must not be negative")
+		}
+		if tenant.API.MaxURLLength < 0 {
+			fail(prefix+".api.max_url_length", "must not be negative")
+		}
+		validateQuota(prefix+".quota", tenant.Quota, fail)
+	}
+
+	keyNames := make(map[string]bool)
+	for i, key := range c.Auth.Keys {
+		prefix := fmt.Sprintf("auth.keys[%d]", i)
+		switch {
+		case key.Name == "":
+			fail(prefix+".name", "must not be empty")
+		case keyNames[key.Name]:
+			fail(prefix+".name", "duplicate key %q", key.Name)
+		}
+		keyNames[key.Name] = true
+
+		if hash, err := hex.DecodeString(key.Hash); err != nil || len(hash) != sha256.Size {
+			fail(prefix+".hash", "must be a hex-encoded SHA-256 digest of the key")
+		}
+		if len(key.Scopes) == 0 {
+			fail(prefix+".scopes", "must not be empty")
+		}
+		for _, scope := range key.Scopes {
+			if !slices.Contains(model.Scopes, model.Scope(scope)) {
+				fail(prefix+".scopes", "unknown scope %q", scope)
+			}
+		}
+		validateQuota(prefix+".quota", key.Quota, fail)
+		if key.Quota.StoredBatches != 0 {
+			fail(prefix+".quota.stored_batches", "is only supported for tenants")
+		}
+	}
+	jwt := c.Auth.JWT
+	if jwt.JWKSURL != "" {
+		if u, err := url.Parse(jwt.JWKSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("auth.jwt.jwks_url", "must be an http(s) URL")
+		}
+		if jwt.Issuer == "" {
+			fail("auth.jwt.issuer", "is required when auth.jwt.jwks_url is set")
+		}
+		if jwt.CacheTTL <= 0 {
+			fail("auth.jwt.cache_ttl", "must be positive")
+		}
+	}
+	if jwt.Leeway < 0 {
+		fail("auth.jwt.leeway", "must not be negative")
+	}
+	for value, scope := range jwt.ScopeMap {
+		if !slices.Contains(model.Scopes, model.Scope(scope)) {
+			fail("auth.jwt.scope_map", "unknown scope %q for %q", scope, value)
+		}
+	}
+	if c.Auth.Enabled && len(c.Auth.Keys) == 0 && jwt.JWKSURL == "" {
+		fail("auth.keys", "at least one key or auth.jwt.jwks_url is required when auth is enabled")
+	}
+
+	if c.Checker.Timeout <= 0 {
+		fail("checker.timeout", "must be positive")
+	}
+	if c.Checker.CertExpiryWarning < 0 {
+		fail("checker.cert_expiry_warning", "must not be negative")
+	}
+	if !slices.Contains(schemeStrategies, c.Checker.SchemeStrategy) {
+		fail("checker.scheme_strategy", "must be one of %s, got %q", strings.Join(schemeStrategies, ", "), c.Checker.SchemeStrategy)
+	}
+
+	crawl := c.Checker.Crawl
+	if crawl.MaxPages <= 0 {
+		fail("checker.crawl.max_pages", "must be positive")
+	}
+	if crawl.MaxDepth < 0 {
+		fail("checker.crawl.max_depth", "must not be negative")
+	}
+	if crawl.MaxLinks <= 0 {
+		fail("checker.crawl.max_links", "must be positive")
+	}
+
+	client := c.Checker.HTTP
+	if client.MaxIdleConnsPerHost < 0 {
+		fail("checker.http.max_idle_conns_per_host", "must not be negative")
+	}
+	for _, header := range client.Headers {
+		if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" {
+			fail("checker.http.headers", "expected \"Name: value\", got %q", header)
+		}
+	}
+	if client.TLSMinVersion != "" && !slices.Contains(tlsVersions, client.TLSMinVersion) {
+		fail("checker.http.tls_min_version", "must be one of %s, got %q", strings.Join(tlsVersions, ", "), client.TLSMinVersion)
+	}
+
+	proxyConfig := c.Checker.Proxy
+	if _, err := proxy.Parse(proxyConfig.URL); err != nil {
+		fail("checker.proxy.url", "%v", err)
+	}
+	for i, rule := range proxyConfig.Rules {
+		key := fmt.Sprintf("checker.proxy.rules[%d]", i)
+		if len(rule.Hosts) == 0 {
+			fail(key+".hosts", "must not be empty")
+		}
+		if _, err := proxy.Parse(rule.URL); err != nil {
+			fail(key+".url", "%v", err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, credential := range c.Checker.Credentials {
+		key := fmt.Sprintf("checker.credentials[%d]", i)
+		switch {
+		case credential.Name == "":
+			fail(key+".name", "must not be empty")
+		case names[credential.Name]:
+			fail(key+".name", "duplicate credential %q", credential.Name)
+		}
+		names[credential.Name] = true
+
+		basic := credential.Username != "" || credential.Password != ""
+		if basic && credential.Token != "" {
+			fail(key, "username/password and token are mutually exclusive")
+		}
+		if !basic && credential.Token == "" && len(credential.Headers) == 0 {
+			fail(key, "requires username/password, token or headers")
+		}
+		for _, header := range credential.Headers {
+			if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" {
+				fail(key+".headers", "expected \"Name: value\"")
+			}
+		}
+		if len(credential.Hosts) == 0 {
+			fail(key+".hosts", "must list the hosts the credential may be sent to")
+		}
+		for _, host := range credential.Hosts {
+			if pattern := strings.TrimPrefix(host, "*."); pattern == "" || strings.ContainsAny(pattern, "*/: ") {
+				fail(key+".hosts", "expected example.com or *.example.com, got %q", host)
+			}
+		}
+	}
+
+	destination := c.Checker.Destination
+	if _, err := netpolicy.ParseCIDRs(destination.AllowCIDRs); err != nil {
+		fail("checker.destination.allow_cidrs", "%v", err)
+	}
+	if _, err := netpolicy.ParseCIDRs(destination.DenyCIDRs); err != nil {
+		fail("checker.destination.deny_cidrs", "%v", err)
+	}
+	for _, port := range destination.AllowedPorts {
+		if port < 1 || port > 65535 {
+			fail("checker.destination.allowed_ports", "invalid port %d", port)
+		}
+	}
+	for _, scheme := range destination.AllowedSchemes {
+		if scheme != "http" && scheme != "https" {
+			fail("checker.destination.allowed_schemes", "unsupported scheme %q", scheme)
+		}
+	}
+
+	if !slices.Contains(storageBackends, c.Storage.Backend) {
+		fail("storage.backend", "must be one of %s, got %q", strings.Join(storageBackends, ", "), c.Storage.Backend)
+	}
+
+	if !slices.Contains(logLevels, c.Logging.Level) {
+		fail("logging.level", "must be one of %s, got %q", strings.Join(logLevels, ", "), c.Logging.Level)
+	}
+	if !slices.Contains(logFormats, c.Logging.Format) {
+		fail("logging.format", "must be one of %s, got %q", strings.Join(logFormats, ", "), c.Logging.Format)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validateQuota(prefix string, quota QuotaConfig, fail func(key, format string, args ...any)) {
+	limits := []struct {
+		key   string
+		value int
+	}{
+		{"checks_per_hour", quota.ChecksPerHour},
+		{"links_per_batch", quota.LinksPerBatch},
+		{"concurrent_batches", quota.ConcurrentBatches},
+		{"stored_batches", quota.StoredBatches},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			fail(prefix+"."+limit.key, "must not be negative")
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/principal"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("insufficient scope")
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*model.Principal, error)
+}
+
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, err error)
+
+type AuthMiddleware struct {
+	h             http.Handler
+	authenticator Authenticator
+	scope         model.Scope
+	writeError    ErrorWriter
+}
+
+func NewAuthMiddleware(h http.Handler, authenticator Authenticator, scope model.Scope, writeError ErrorWriter) http.Handler {
+	return &AuthMiddleware{
+		h:             h,
+		authenticator: authenticator,
+		scope:         scope,
+		writeError:    writeError,
+	}
+}
+
+func (m *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := credentialsFromRequest(r)
+	if token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="link-checker"`)
+		m.writeError(w, r, ErrUnauthenticated)
+		return
+	}
+
+	caller, err := m.authenticator.Authenticate(r.Context(), token)
+	if err != nil {
+		log.Printf("Rejected credentials for %s %s: %v", r.Method, r.URL.Path, err)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="link-checker", error="invalid_token"`)
+		m.writeError(w, r, fmt.Errorf("%w: invalid credentials", ErrUnauthenticated))
+		return
+	}
+
+	if !caller.HasScope(m.scope) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="link-checker", error="insufficient_scope", scope=%q`, m.scope))
+		m.writeError(w, r, fmt.Errorf("%w: %s required", ErrForbidden, m.scope))
+		return
+	}
+
+	ctx := principal.WithPrincipal(r.Context(), caller)
+	m.h.ServeHTTP(w, r.WithContext(tenant.WithTenant(ctx, caller.Tenant)))
+}
+
+func credentialsFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/principal"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
+)
+
+type staticAuthenticator map[string]*model.Principal
+
+func (a staticAuthenticator) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	if caller, ok := a[token]; ok {
+		return caller, nil
+	}
+	return nil, errors.New("unknown token")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	authenticator := staticAuthenticator{
+		"writer": {Subject: "w", Tenant: "team-a", Scopes: []model.Scope{model.ScopeCheckWrite}},
+		"reader": {Subject: "r", Scopes: []model.Scope{model.ScopeReportRead}},
+		"admin":  {Subject: "a", Scopes: []model.Scope{model.ScopeAdmin}},
+	}
+
+	var seen *model.Principal
+	var seenTenant string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = principal.FromContext(r.Context())
+		seenTenant = tenant.FromContext(r.Context())
+	})
+	writeError := func(w http.ResponseWriter, r *http.Request, err error) {
+		switch {
+		case errors.Is(err, ErrUnauthenticated):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, ErrForbidden):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+	handler := NewAuthMiddleware(next, authenticator, model.ScopeCheckWrite, writeError)
+
+	tests := []struct {
+		name      string
+		header    string
+		value     string
+		status    int
+		challenge string
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized, `realm="link-checker"`},
+		{"unknown key", APIKeyHeader, "nope", http.StatusUnauthorized, "invalid_token"},
+		{"wrong scope", APIKeyHeader, "reader", http.StatusForbidden, `scope="check:write"`},
+		{"api key header", APIKeyHeader, "writer", http.StatusOK, ""},
+		{"bearer token", "Authorization", "Bearer writer", http.StatusOK, ""},
+		{"basic is ignored", "Authorization", "Basic writer", http.StatusUnauthorized, ""},
+		{"admin", "Authorization", "bearer admin", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest("POST", "/api/check-links", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if challenge := w.Header().Get("WWW-Authenticate"); !strings.Contains(challenge, tt.challenge) {
+				t.Errorf("Expected challenge to contain %q, got %q", tt.challenge, challenge)
+			}
+			if (tt.status == http.StatusOK) != (seen != nil) {
+				t.Errorf("Expected principal in context only for accepted requests, got %v", seen)
+			}
+			if seen != nil && seen.Tenant != "" && seenTenant != seen.Tenant {
+				t.Errorf("Expected tenant of the principal in context, got %q", seenTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/principal"
+)
+
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+
+	defaultRateLimitIdleTimeout = 10 * time.Minute
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrRateLimited, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RateLimit is a token bucket holding up to Burst tokens and gaining Refill
+// tokens every Period.
+type RateLimit struct {
+	Burst  int
+	Refill int
+	Period time.Duration
+}
+
+func (l RateLimit) rate() float64 {
+	return float64(l.Refill) / l.Period.Seconds()
+}
+
+// window is the time an empty bucket takes to fill up.
+func (l RateLimit) window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.rate() * float64(time.Second))
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token, zero when allowed.
+	RetryAfter time.Duration
+}
+
+type RateLimitStore interface {
+	Take(key string, limit RateLimit, now time.Time) RateLimitResult
+}
+
+// RateLimitKey maps a request to its bucket.
+type RateLimitKey func(r *http.Request) string
+
+func RateLimitByIP(r *http.Request) string {
+	return "ip:" + clientIP(r)
+}
+
+// RateLimitByPrincipal keys by the authenticated caller, so the middleware
+// must run after AuthMiddleware; anonymous requests fall back to the client
+// IP.
+func RateLimitByPrincipal(r *http.Request) string {
+	caller := principal.FromContext(r.Context())
+	if caller == nil {
+		return RateLimitByIP(r)
+	}
+	return "principal:" + string(caller.Method) + ":" + caller.Subject
+}
+
+// RateLimitByRoute shares one bucket among all requests to the route
+// registered with pattern.
+func RateLimitByRoute(pattern string) RateLimitKey {
+	return func(r *http.Request) string {
+		return "route:" + pattern
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type RateLimitMiddleware struct {
+	h          http.Handler
+	store      RateLimitStore
+	limit      RateLimit
+	key        RateLimitKey
+	writeError ErrorWriter
+	now        func() time.Time
+}
+
+func NewRateLimitMiddleware(h http.Handler, store RateLimitStore, limit RateLimit, key RateLimitKey, writeError ErrorWriter) http.Handler {
+	return &RateLimitMiddleware{
+		h:          h,
+		store:      store,
+		limit:      limit,
+		key:        key,
+		writeError: writeError,
+		now:        time.Now,
+	}
+}
+
+func (m *RateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if m.take(w, r) {
+		m.h.ServeHTTP(w, r)
+	}
+}
+
+// RateLimitFailures charges the errors written by writeError to the bucket
+// of key. Wrapped around the error writer of AuthMiddleware, it throttles
+// callers whose credentials are rejected before they reach a rate limit
+// keyed by principal.
+func RateLimitFailures(store RateLimitStore, limit RateLimit, key RateLimitKey, writeError ErrorWriter) ErrorWriter {
+	m := &RateLimitMiddleware{
+		store:      store,
+		limit:      limit,
+		key:        key,
+		writeError: writeError,
+		now:        time.Now,
+	}
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		if m.take(w, r) {
+			writeError(w, r, err)
+		}
+	}
+}
+
+// take consumes a token and sets the rate limit headers. When the bucket is
+// empty it writes the error and returns false.
+func (m *RateLimitMiddleware) take(w http.ResponseWriter, r *http.Request) bool {
+	result := m.store.Take(m.key(r), m.limit, m.now())
+
+	header := w.Header()
+	header.Set(RateLimitLimitHeader, strconv.Itoa(m.limit.Burst))
+	header.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+	header.Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+	header.Set(RateLimitPolicyHeader, fmt.Sprintf("%d;w=%d", m.limit.Burst, ceilSeconds(m.limit.window())))
+
+	if !result.Allowed {
+		m.writeError(w, r, &RateLimitedError{RetryAfter: result.RetryAfter})
+		return false
+	}
+	return true
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed.Seconds()*b.limit.rate())
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) full() bool {
+	return b.tokens >= float64(b.limit.Burst)
+}
+
+// InMemoryRateLimitStore keeps one bucket per key. Buckets idle for longer
+// than the idle timeout are dropped once they have refilled, so eviction
+// never hands a client extra tokens.
+type InMemoryRateLimitStore struct {
+	mu          sync.Mutex
+	buckets     map[string]*tokenBucket
+	idleTimeout time.Duration
+	lastSweep   time.Time
+}
+
+func NewInMemoryRateLimitStore(idleTimeout time.Duration) *InMemoryRateLimitStore {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultRateLimitIdleTimeout
+	}
+	return &InMemoryRateLimitStore{
+		buckets:     make(map[string]*tokenBucket),
+		idleTimeout: idleTimeout,
+	}
+}
+
+func (s *InMemoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) RateLimitResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= s.idleTimeout {
+		s.evictIdle(now)
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = bucket
+	}
+	// A reload may change the limit; keep the tokens, capped to the new burst.
+	bucket.limit = limit
+	bucket.refill(now)
+	bucket.tokens = math.Min(bucket.tokens, float64(limit.Burst))
+
+	rate := limit.rate()
+	result := RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = time.Duration((float64(limit.Burst) - bucket.tokens) / rate * float64(time.Second))
+	return result
+}
+
+func (s *InMemoryRateLimitStore) evictIdle(now time.Time) {
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.last) < s.idleTimeout {
+			continue
+		}
+		bucket.refill(now)
+		if bucket.full() {
+			delete(s.buckets, key)
+		}
+	}
+}
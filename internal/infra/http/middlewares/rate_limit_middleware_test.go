@@ -0,0 +1,159 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/principal"
+)
+
+func TestInMemoryRateLimitStore_Take(t *testing.T) {
+	store := NewInMemoryRateLimitStore(time.Minute)
+	limit := RateLimit{Burst: 2, Refill: 1, Period: time.Second}
+	now := time.Date(2025, 12, 5, 10, 0, 0, 0, time.UTC)
+
+	for i, remaining := range []int{1, 0} {
+		result := store.Take("a", limit, now)
+		if !result.Allowed || result.Remaining != remaining {
+			t.Fatalf("Request %d: expected allowed with %d remaining, got %+v", i, remaining, result)
+		}
+	}
+
+	result := store.Take("a", limit, now)
+	if result.Allowed {
+		t.Fatal("Expected the empty bucket to reject")
+	}
+	if result.RetryAfter != time.Second || result.Reset != 2*time.Second {
+		t.Errorf("Expected retry after 1s and reset in 2s, got %+v", result)
+	}
+
+	if result := store.Take("b", limit, now); !result.Allowed {
+		t.Error("Expected other keys to have their own bucket")
+	}
+
+	if result := store.Take("a", limit, now.Add(500*time.Millisecond)); result.Allowed || result.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Expected half a token after 500ms, got %+v", result)
+	}
+	if result := store.Take("a", limit, now.Add(time.Second)); !result.Allowed {
+		t.Errorf("Expected a refilled token after 1s, got %+v", result)
+	}
+}
+
+func TestInMemoryRateLimitStore_EvictsIdleBuckets(t *testing.T) {
+	store := NewInMemoryRateLimitStore(time.Minute)
+	slow := RateLimit{Burst: 10, Refill: 1, Period: time.Hour}
+	fast := RateLimit{Burst: 10, Refill: 10, Period: time.Second}
+	now := time.Date(2025, 12, 5, 10, 0, 0, 0, time.UTC)
+
+	store.Take("slow", slow, now)
+	store.Take("fast", fast, now)
+	store.Take("other", fast, now.Add(2*time.Minute))
+
+	if _, ok := store.buckets["fast"]; ok {
+		t.Error("Expected the idle refilled bucket to be evicted")
+	}
+	if _, ok := store.buckets["slow"]; !ok {
+		t.Error("Expected the bucket that has not refilled yet to be kept")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	var seen error
+	writeError := func(w http.ResponseWriter, r *http.Request, err error) {
+		seen = err
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	limit := RateLimit{Burst: 2, Refill: 1, Period: 10 * time.Second}
+	handler := NewRateLimitMiddleware(next, NewInMemoryRateLimitStore(time.Minute), limit, RateLimitByIP, writeError).(*RateLimitMiddleware)
+	now := time.Date(2025, 12, 5, 10, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/check-links", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("192.0.2.1:1234")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	headers := map[string]string{
+		RateLimitLimitHeader:     "2",
+		RateLimitRemainingHeader: "1",
+		RateLimitResetHeader:     "10",
+		RateLimitPolicyHeader:    "2;w=20",
+	}
+	for name, want := range headers {
+		if got := w.Header().Get(name); got != want {
+			t.Errorf("Expected %s %q, got %q", name, want, got)
+		}
+	}
+
+	request("192.0.2.1:5678")
+	w = request("192.0.2.1:1234")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+	var limited *RateLimitedError
+	if !errors.As(seen, &limited) || limited.RetryAfter != 10*time.Second {
+		t.Errorf("Expected RateLimitedError with 10s retry, got %v", seen)
+	}
+	if got := w.Header().Get(RateLimitRemainingHeader); got != "0" {
+		t.Errorf("Expected no remaining requests, got %q", got)
+	}
+
+	if w := request("192.0.2.2:1234"); w.Code != http.StatusOK {
+		t.Errorf("Expected other clients to be allowed, got %d", w.Code)
+	}
+}
+
+func TestRateLimitFailures(t *testing.T) {
+	var seen []error
+	writeError := func(w http.ResponseWriter, r *http.Request, err error) {
+		seen = append(seen, err)
+	}
+	limit := RateLimit{Burst: 1, Refill: 1, Period: time.Hour}
+	handler := NewAuthMiddleware(http.NotFoundHandler(), staticAuthenticator{}, model.ScopeCheckWrite,
+		RateLimitFailures(NewInMemoryRateLimitStore(time.Minute), limit, RateLimitByIP, writeError))
+
+	for range 2 {
+		req := httptest.NewRequest("GET", "/api/usage", nil)
+		req.Header.Set(APIKeyHeader, "junk")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(seen) != 2 || !errors.Is(seen[0], ErrUnauthenticated) || !errors.Is(seen[1], ErrRateLimited) {
+		t.Errorf("Expected the second failure to be rate limited, got %v", seen)
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/keys/1", nil)
+	req.RemoteAddr = "[2001:db8::1]:443"
+
+	if got := RateLimitByIP(req); got != "ip:2001:db8::1" {
+		t.Errorf("Unexpected IP key %q", got)
+	}
+	if got := RateLimitByRoute("POST /api/keys/{id}")(req); got != "route:POST /api/keys/{id}" {
+		t.Errorf("Unexpected route key %q", got)
+	}
+
+	req.Header.Set(APIKeyHeader, "junk")
+	if got := RateLimitByPrincipal(req); got != "ip:2001:db8::1" {
+		t.Errorf("Expected unauthenticated requests to fall back to the IP, got %q", got)
+	}
+
+	caller := &model.Principal{Subject: "k1", Method: model.AuthAPIKey}
+	req = req.WithContext(principal.WithPrincipal(req.Context(), caller))
+	if got := RateLimitByPrincipal(req); got != "principal:api_key:k1" {
+		t.Errorf("Unexpected principal key %q", got)
+	}
+}
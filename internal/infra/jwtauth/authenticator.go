@@ -0,0 +1,125 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const DefaultScopeClaim = "scope"
+
+var validMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}
+
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+type Authenticator struct {
+	Keys     KeyProvider
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+
+	// ScopeClaim holds either a space-separated string or a list. Values
+	// are translated through ScopeMap; values that already name a scope
+	// are accepted as is.
+	ScopeClaim string
+	ScopeMap   map[string]model.Scope
+
+	// TenantClaim, when set, must be present in every token and names the
+	// tenant of the caller. Without it all tokens share the default tenant.
+	TenantClaim string
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(a.Issuer),
+		jwt.WithLeeway(a.Leeway),
+	}
+	if a.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.Keys.Key(ctx, kid)
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, errors.New("invalid token: missing sub claim")
+	}
+
+	var tenant string
+	if a.TenantClaim != "" {
+		if tenant = firstString(claims, a.TenantClaim); tenant == "" {
+			return nil, fmt.Errorf("invalid token: missing %s claim", a.TenantClaim)
+		}
+	}
+
+	return &model.Principal{
+		Subject: subject,
+		Name:    firstString(claims, "preferred_username", "client_id", "azp"),
+		Tenant:  tenant,
+		Method:  model.AuthJWT,
+		Scopes:  a.scopes(claims),
+	}, nil
+}
+
+func (a *Authenticator) scopes(claims jwt.MapClaims) []model.Scope {
+	claim := a.ScopeClaim
+	if claim == "" {
+		claim = DefaultScopeClaim
+	}
+
+	var values []string
+	switch value := claims[claim].(type) {
+	case string:
+		values = strings.Fields(value)
+	case []any:
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var scopes []model.Scope
+	for _, value := range values {
+		scope, ok := a.ScopeMap[value]
+		if !ok {
+			scope = model.Scope(value)
+		}
+		if slices.Contains(model.Scopes, scope) && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func firstString(claims jwt.MapClaims, names ...string) string {
+	for _, name := range names {
+		if value, ok := claims[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// LooksLikeJWT tells bearer JWTs apart from opaque API keys.
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2 && strings.HasPrefix(token, "eyJ")
+}
@@ -0,0 +1,160 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://idp.example.com/"
+	testAudience = "link-checker"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                testIssuer,
+		"aud":                testAudience,
+		"sub":                "service-account-1",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "ci-bot",
+		"scope":              "openid check:write",
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server := newJWKSServer(t, rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("ec", &ecKey.PublicKey))
+
+	authenticator := &Authenticator{
+		Keys:     NewKeySet(server.URL, server.Client(), time.Hour),
+		Issuer:   testIssuer,
+		Audience: testAudience,
+	}
+
+	with := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := validClaims()
+		change(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"rs256", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()), true},
+		{"es256", sign(t, jwt.SigningMethodES256, "ec", ecKey, validClaims()), true},
+		{"wrong issuer", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com/" })), false},
+		{"wrong audience", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c jwt.MapClaims) { c["aud"] = "other" })), false},
+		{"expired", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() })), false},
+		{"no expiry", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c jwt.MapClaims) { delete(c, "exp") })), false},
+		{"no subject", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, with(func(c jwt.MapClaims) { delete(c, "sub") })), false},
+		{"unknown kid", sign(t, jwt.SigningMethodRS256, "other", rsaKey, validClaims()), false},
+		{"key type mismatch", sign(t, jwt.SigningMethodES256, "rsa", ecKey, validClaims()), false},
+		{"hs256", sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), validClaims()), false},
+		{"none", sign(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims()), false},
+		{"rs384", sign(t, jwt.SigningMethodRS384, "rsa", rsaKey, validClaims()), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), tt.token)
+			if tt.valid != (err == nil) {
+				t.Fatalf("Expected valid=%v, got principal %v, error %v", tt.valid, principal, err)
+			}
+			if !tt.valid {
+				return
+			}
+			if principal.Subject != "service-account-1" || principal.Name != "ci-bot" || principal.Method != model.AuthJWT {
+				t.Errorf("Unexpected principal: %+v", principal)
+			}
+			if !slices.Equal(principal.Scopes, []model.Scope{model.ScopeCheckWrite}) {
+				t.Errorf("Expected check:write scope, got %v", principal.Scopes)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_ScopeMapping(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := newJWKSServer(t, rsaJWK("rsa", &key.PublicKey))
+
+	authenticator := &Authenticator{
+		Keys:       NewKeySet(server.URL, server.Client(), time.Hour),
+		Issuer:     testIssuer,
+		ScopeClaim: "roles",
+		ScopeMap: map[string]model.Scope{
+			"linkchecker.readers": model.ScopeReportRead,
+			"linkchecker.admins":  model.ScopeAdmin,
+		},
+	}
+
+	claims := validClaims()
+	claims["roles"] = []string{"linkchecker.readers", "linkchecker.admins", "unrelated", "linkchecker.readers"}
+	principal, err := authenticator.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key, claims))
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+
+	expected := []model.Scope{model.ScopeReportRead, model.ScopeAdmin}
+	if !slices.Equal(principal.Scopes, expected) {
+		t.Errorf("Expected %v, got %v", expected, principal.Scopes)
+	}
+}
+
+func TestAuthenticator_TenantClaim(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := newJWKSServer(t, rsaJWK("rsa", &key.PublicKey))
+
+	authenticator := &Authenticator{
+		Keys:        NewKeySet(server.URL, server.Client(), time.Hour),
+		Issuer:      testIssuer,
+		TenantClaim: "org",
+	}
+
+	claims := validClaims()
+	claims["org"] = "team-a"
+	principal, err := authenticator.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key, claims))
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if principal.Tenant != "team-a" {
+		t.Errorf("Expected tenant team-a, got %q", principal.Tenant)
+	}
+
+	if _, err := authenticator.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key, validClaims())); err == nil {
+		t.Error("Expected token without tenant claim to be rejected")
+	}
+}
+
+func TestLooksLikeJWT(t *testing.T) {
+	if !LooksLikeJWT("eyJhbGciOiJSUzI1NiJ9.eyJzdWIiOiJ4In0.c2ln") {
+		t.Error("Expected JWT to be recognized")
+	}
+	for _, token := range []string{"lck_abc", "eyJ.only-one-dot", "a.b.c"} {
+		if LooksLikeJWT(token) {
+			t.Errorf("Expected %q not to look like a JWT", token)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) jwk {
+	return jwk{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) jwk {
+	return jwk{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+type jwksServer struct {
+	*httptest.Server
+	keys    atomic.Value
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, keys ...jwk) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	s.keys.Store(keys)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"keys": s.keys.Load()})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestKeySet_ParsesAndCachesKeys(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server := newJWKSServer(t,
+		rsaJWK("rsa", &rsaKey.PublicKey),
+		ecJWK("ec", &ecKey.PublicKey),
+		jwk{Kty: "oct", Kid: "hmac"},
+		jwk{Kty: "RSA", Kid: "enc", Use: "enc"},
+	)
+	keys := NewKeySet(server.URL, server.Client(), time.Hour)
+
+	got, err := keys.Key(context.Background(), "rsa")
+	if err != nil {
+		t.Fatalf("Key(rsa) failed: %v", err)
+	}
+	if !rsaKey.PublicKey.Equal(got) {
+		t.Error("Expected RSA key to match")
+	}
+
+	got, err = keys.Key(context.Background(), "ec")
+	if err != nil {
+		t.Fatalf("Key(ec) failed: %v", err)
+	}
+	if !ecKey.PublicKey.Equal(got) {
+		t.Error("Expected EC key to match")
+	}
+
+	for _, kid := range []string{"hmac", "enc"} {
+		if _, err := keys.Key(context.Background(), kid); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("Key(%s): expected ErrUnknownKey, got %v", kid, err)
+		}
+	}
+
+	if fetches := server.fetches.Load(); fetches != 1 {
+		t.Errorf("Expected a single JWKS fetch, got %d", fetches)
+	}
+}
+
+func TestKeySet_RefreshesAfterTTL(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := newJWKSServer(t, rsaJWK("old", &oldKey.PublicKey))
+	keys := NewKeySet(server.URL, server.Client(), time.Hour)
+
+	if _, err := keys.Key(context.Background(), "old"); err != nil {
+		t.Fatalf("Key(old) failed: %v", err)
+	}
+
+	server.keys.Store([]jwk{rsaJWK("new", &newKey.PublicKey)})
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+	if _, err := keys.Key(context.Background(), "new"); err != nil {
+		t.Fatalf("Expected rotated key after TTL, got %v", err)
+	}
+	if _, err := keys.Key(context.Background(), "old"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected removed key to be unknown, got %v", err)
+	}
+}
+
+func TestKeySet_ServesCachedKeysWhenProviderFails(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := newJWKSServer(t, rsaJWK("k", &key.PublicKey))
+	keys := NewKeySet(server.URL, server.Client(), time.Hour)
+
+	if _, err := keys.Key(context.Background(), "k"); err != nil {
+		t.Fatalf("Key failed: %v", err)
+	}
+
+	server.Close()
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := keys.Key(context.Background(), "k"); err != nil {
+		t.Errorf("Expected cached key while provider is down, got %v", err)
+	}
+}
+
+func TestKeySet_ServesCachedKeysDuringRefresh(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": []jwk{rsaJWK("k", &key.PublicKey)}})
+	}))
+	defer server.Close()
+	defer close(release)
+	keys := NewKeySet(server.URL, server.Client(), time.Hour)
+
+	if _, err := keys.Key(context.Background(), "k"); err != nil {
+		t.Fatalf("Key failed: %v", err)
+	}
+
+	keys.mu.Lock()
+	keys.fetchedAt = time.Now().Add(-time.Minute)
+	keys.mu.Unlock()
+	go keys.Key(context.Background(), "rotated")
+	for fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(context.Background(), "k")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected cached key during refresh, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected cached key lookups not to wait for the JWKS refresh")
+	}
+}
@@ -0,0 +1,204 @@
+package netpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrBlocked = errors.New("destination blocked by policy")
+
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+type Policy struct {
+	AllowPrivate   bool
+	AllowCIDRs     []netip.Prefix
+	DenyCIDRs      []netip.Prefix
+	AllowHosts     []string
+	DenyHosts      []string
+	AllowedPorts   []int
+	AllowedSchemes []string
+}
+
+func ParseCIDRs(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func (p *Policy) CheckURL(u *url.URL) error {
+	if p == nil {
+		return nil
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if len(p.AllowedSchemes) > 0 && !slices.Contains(p.AllowedSchemes, scheme) {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrBlocked, u.Scheme)
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch scheme {
+		case "https":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+	return p.checkHost(u.Hostname(), port)
+}
+
+// CheckProxied vets a target that will be reached through a proxy, where the
+// dial-time check never sees the target address. Names that do not resolve
+// locally are left to the proxy.
+func (p *Policy) CheckProxied(ctx context.Context, u *url.URL) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.CheckURL(u); err != nil {
+		return err
+	}
+
+	ips, err := p.lookup(ctx, u.Hostname())
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if err := p.CheckAddress(u.Hostname(), ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Policy) CheckAddress(host string, ip netip.Addr) error {
+	ip = ip.Unmap()
+
+	for _, prefix := range p.DenyCIDRs {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s resolves to denied address %s", ErrBlocked, host, ip)
+		}
+	}
+	if p.AllowPrivate || matchesHost(p.AllowHosts, host) {
+		return nil
+	}
+	for _, prefix := range p.AllowCIDRs {
+		if prefix.Contains(ip) {
+			return nil
+		}
+	}
+	if isInternal(ip) {
+		return fmt.Errorf("%w: %s resolves to internal address %s", ErrBlocked, host, ip)
+	}
+	return nil
+}
+
+func (p *Policy) DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkHost(host, port); err != nil {
+			return nil, err
+		}
+
+		ips, err := p.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range ips {
+			if err := p.CheckAddress(host, ip); err != nil {
+				lastErr = err
+				continue
+			}
+			// Dial the vetted address so a second lookup cannot rebind the host.
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, lastErr
+	}
+}
+
+func (p *Policy) checkHost(host, port string) error {
+	if matchesHost(p.DenyHosts, host) {
+		return fmt.Errorf("%w: host %s is denied", ErrBlocked, host)
+	}
+
+	if len(p.AllowedPorts) > 0 {
+		n, err := strconv.Atoi(port)
+		if err != nil || !slices.Contains(p.AllowedPorts, n) {
+			return fmt.Errorf("%w: port %s is not allowed", ErrBlocked, port)
+		}
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return p.CheckAddress(host, ip)
+	}
+	return nil
+}
+
+func (p *Policy) lookup(ctx context.Context, host string) ([]netip.Addr, error) {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{ip}, nil
+	}
+
+	return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+}
+
+func matchesHost(patterns []string, host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func isInternal(ip netip.Addr) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
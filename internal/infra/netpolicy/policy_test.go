@@ -0,0 +1,146 @@
+package netpolicy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+func TestPolicy_CheckAddress(t *testing.T) {
+	allow, _ := ParseCIDRs([]string{"10.1.0.0/16"})
+	deny, _ := ParseCIDRs([]string{"203.0.113.0/24"})
+	policy := &Policy{
+		AllowCIDRs: allow,
+		DenyCIDRs:  deny,
+		AllowHosts: []string{"*.corp.example.com"},
+	}
+
+	tests := []struct {
+		host    string
+		ip      string
+		blocked bool
+	}{
+		{"localhost", "127.0.0.1", true},
+		{"metadata", "169.254.169.254", true},
+		{"internal", "10.0.0.1", true},
+		{"internal", "192.168.1.1", true},
+		{"cgnat", "100.64.1.1", true},
+		{"v6-loopback", "::1", true},
+		{"v6-ula", "fd00::1", true},
+		{"mapped", "::ffff:127.0.0.1", true},
+		{"unspecified", "0.0.0.0", true},
+		{"public", "93.184.216.34", false},
+		{"allowed-range", "10.1.2.3", false},
+		{"wiki.corp.example.com", "10.2.0.1", false},
+		{"denied", "203.0.113.7", true},
+	}
+
+	for _, tt := range tests {
+		err := policy.CheckAddress(tt.host, netip.MustParseAddr(tt.ip))
+		if blocked := errors.Is(err, ErrBlocked); blocked != tt.blocked {
+			t.Errorf("CheckAddress(%s, %s) blocked = %v, expected %v", tt.host, tt.ip, blocked, tt.blocked)
+		}
+	}
+}
+
+func TestPolicy_CheckURL(t *testing.T) {
+	policy := &Policy{
+		DenyHosts:      []string{"evil.example.com"},
+		AllowedPorts:   []int{80, 443},
+		AllowedSchemes: []string{"http", "https"},
+	}
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"https://example.com/", false},
+		{"http://example.com:80/", false},
+		{"http://example.com:8080/", true},
+		{"ftp://example.com/", true},
+		{"https://EVIL.example.com./", true},
+		{"http://127.0.0.1/", true},
+		{"http://[::1]/", true},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if blocked := errors.Is(policy.CheckURL(u), ErrBlocked); blocked != tt.blocked {
+			t.Errorf("CheckURL(%s) blocked = %v, expected %v", tt.url, blocked, tt.blocked)
+		}
+	}
+
+	var nilPolicy *Policy
+	if err := nilPolicy.CheckURL(&url.URL{Scheme: "http", Host: "127.0.0.1"}); err != nil {
+		t.Errorf("Expected nil policy to allow everything, got %v", err)
+	}
+}
+
+func TestPolicy_DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dial := func(policy *Policy) error {
+		client := &http.Client{Transport: &http.Transport{DialContext: policy.DialContext(&net.Dialer{})}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := dial(&Policy{}); !errors.Is(err, ErrBlocked) {
+		t.Errorf("Expected loopback to be blocked at dial time, got %v", err)
+	}
+
+	allow, _ := ParseCIDRs([]string{"127.0.0.0/8"})
+	if err := dial(&Policy{AllowCIDRs: allow}); err != nil {
+		t.Errorf("Expected allowed range to be dialed, got %v", err)
+	}
+}
+
+func TestPolicy_DialContext_ResolvesBeforeChecking(t *testing.T) {
+	policy := &Policy{}
+
+	_, err := policy.DialContext(&net.Dialer{})(context.Background(), "tcp", "localhost:80")
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("Expected localhost to be blocked after resolution, got %v", err)
+	}
+}
+
+func TestPolicy_CheckProxied(t *testing.T) {
+	policy := &Policy{}
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"http://127.0.0.1/", true},
+		{"http://localhost/", true},
+		{"http://93.184.216.34/", false},
+		{"http://unresolvable.invalid/", false},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if blocked := errors.Is(policy.CheckProxied(context.Background(), u), ErrBlocked); blocked != tt.blocked {
+			t.Errorf("CheckProxied(%s) blocked = %v, expected %v", tt.url, blocked, tt.blocked)
+		}
+	}
+
+	var nilPolicy *Policy
+	if err := nilPolicy.CheckProxied(context.Background(), &url.URL{Scheme: "http", Host: "127.0.0.1"}); err != nil {
+		t.Errorf("Expected nil policy to allow everything, got %v", err)
+	}
+}
+
+func TestParseCIDRs_Invalid(t *testing.T) {
+	if _, err := ParseCIDRs([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+}
@@ -0,0 +1,178 @@
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var supportedSchemes = map[string]string{
+	"http":    "80",
+	"https":   "443",
+	"socks5":  "1080",
+	"socks5h": "1080",
+}
+
+type Rule struct {
+	Hosts []string
+	URL   *url.URL
+}
+
+type Selector struct {
+	HTTP    *url.URL
+	HTTPS   *url.URL
+	Rules   []Rule
+	NoProxy []string
+}
+
+func Parse(raw string) (*url.URL, error) {
+	if raw == "" || raw == "direct" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q", redact(raw))
+	}
+	if _, ok := supportedSchemes[u.Scheme]; !ok || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: expected http, https, socks5 or socks5h scheme and a host", u.Redacted())
+	}
+	return u, nil
+}
+
+func FromEnvironment() (*Selector, error) {
+	selector := &Selector{}
+
+	var err error
+	if selector.HTTP, err = Parse(getenv("HTTP_PROXY")); err != nil {
+		return nil, err
+	}
+	if selector.HTTPS, err = Parse(getenv("HTTPS_PROXY")); err != nil {
+		return nil, err
+	}
+	for _, entry := range strings.Split(getenv("NO_PROXY"), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			selector.NoProxy = append(selector.NoProxy, entry)
+		}
+	}
+	return selector, nil
+}
+
+func (s *Selector) ProxyFor(target *url.URL) *url.URL {
+	if s == nil {
+		return nil
+	}
+
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	if port == "" {
+		port = supportedSchemes[target.Scheme]
+	}
+
+	for _, entry := range s.NoProxy {
+		if matchesNoProxy(entry, host, port) {
+			return nil
+		}
+	}
+
+	for _, rule := range s.Rules {
+		for _, pattern := range rule.Hosts {
+			if matchesHost(pattern, host) {
+				return rule.URL
+			}
+		}
+	}
+
+	if target.Scheme == "https" {
+		return s.HTTPS
+	}
+	return s.HTTP
+}
+
+func (s *Selector) Addresses() []string {
+	if s == nil {
+		return nil
+	}
+
+	proxies := []*url.URL{s.HTTP, s.HTTPS}
+	for _, rule := range s.Rules {
+		proxies = append(proxies, rule.URL)
+	}
+
+	var addresses []string
+	for _, u := range proxies {
+		if u != nil {
+			addresses = append(addresses, Address(u))
+		}
+	}
+	return addresses
+}
+
+func Address(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = supportedSchemes[u.Scheme]
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func Display(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func matchesHost(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == pattern
+}
+
+func matchesNoProxy(entry, host, port string) bool {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+	if entry == "*" {
+		return true
+	}
+
+	if prefix, err := netip.ParsePrefix(entry); err == nil {
+		ip, err := netip.ParseAddr(host)
+		return err == nil && prefix.Contains(ip.Unmap())
+	}
+
+	if h, p, err := net.SplitHostPort(entry); err == nil {
+		if p != port {
+			return false
+		}
+		entry = h
+	}
+
+	if ip, err := netip.ParseAddr(entry); err == nil {
+		hostIP, err := netip.ParseAddr(host)
+		return err == nil && ip == hostIP.Unmap()
+	}
+
+	entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+	return host == entry || strings.HasSuffix(host, "."+entry)
+}
+
+func getenv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return os.Getenv(strings.ToLower(name))
+}
+
+func redact(raw string) string {
+	if at := strings.LastIndex(raw, "@"); at >= 0 {
+		if scheme := strings.Index(raw, "://"); scheme >= 0 && scheme < at {
+			return raw[:scheme+3] + "xxxxx" + raw[at:]
+		}
+	}
+	return raw
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/repository"
 	"github.com/eightjhonydolly/05.12.2025/internal/domain/links/service"
+	"github.com/eightjhonydolly/05.12.2025/internal/domain/tenant"
 )
 
 func TestFullWorkflow_ServiceToRepository(t *testing.T) {
@@ -19,15 +20,15 @@ func TestFullWorkflow_ServiceToRepository(t *testing.T) {
 		t.Fatalf("CheckLinks failed: %v", err)
 	}
 
-	if batch.ID != 1 {
-		t.Errorf("Expected batch ID 1, got %d", batch.ID)
+	if batch.ID == "" || batch.Tenant != tenant.Default {
+		t.Errorf("Expected batch with ID in the default tenant, got %q in %q", batch.ID, batch.Tenant)
 	}
 
 	if len(batch.Links) != 2 {
 		t.Errorf("Expected 2 links, got %d", len(batch.Links))
 	}
 
-	retrieved, err := repo.GetBatch(batch.ID)
+	retrieved, err := repo.GetBatch(batch.Tenant, batch.ID)
 	if err != nil {
 		t.Fatalf("GetBatch failed: %v", err)
 	}
@@ -36,7 +37,7 @@ func TestFullWorkflow_ServiceToRepository(t *testing.T) {
 		t.Fatal("Batch not found in repository")
 	}
 
-	pdfData, err := svc.GenerateReport([]int{batch.ID})
+	pdfData, err := svc.GenerateReport(context.Background(), []string{batch.ID})
 	if err != nil {
 		t.Fatalf("GenerateReport failed: %v", err)
 	}
@@ -50,28 +51,32 @@ func TestConcurrentLinkChecking(t *testing.T) {
 	repo := repository.NewInMemoryLinkRepository()
 	svc := service.NewLinkService(repo)
 
-	done := make(chan bool, 3)
+	done := make(chan string, 3)
 
 	for i := 0; i < 3; i++ {
 		go func(id int) {
 			urls := []string{"httpbin.org"}
-			_, err := svc.CheckLinks(context.Background(), urls)
+			batch, err := svc.CheckLinks(context.Background(), urls)
 			if err != nil {
 				t.Errorf("Concurrent CheckLinks %d failed: %v", id, err)
+				done <- ""
+				return
 			}
-			done <- true
+			done <- batch.ID
 		}(i)
 	}
 
+	var ids []string
 	for i := 0; i < 3; i++ {
 		select {
-		case <-done:
+		case id := <-done:
+			ids = append(ids, id)
 		case <-time.After(30 * time.Second):
 			t.Fatal("Timeout waiting for concurrent operations")
 		}
 	}
 
-	batches, err := repo.GetBatches([]int{1, 2, 3})
+	batches, err := repo.GetBatches(tenant.Default, ids)
 	if err != nil {
 		t.Fatalf("GetBatches failed: %v", err)
 	}